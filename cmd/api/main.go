@@ -18,6 +18,8 @@ import (
 	"github.com/leozw/uptime-guardian/internal/config"
 	"github.com/leozw/uptime-guardian/internal/db"
 	"github.com/leozw/uptime-guardian/internal/metrics"
+	"github.com/leozw/uptime-guardian/internal/notifications"
+	"github.com/leozw/uptime-guardian/internal/secrets"
 	"github.com/leozw/uptime-guardian/pkg/keycloak"
 	"go.uber.org/zap"
 )
@@ -57,6 +59,21 @@ func main() {
 	// Initialize metrics collector
 	metricsCollector := metrics.NewCollector(cfg.Mimir)
 
+	// Initialize secrets. The API encrypts them and only resolves them to send
+	// test notifications; checks and alerts resolve them in the worker.
+	var keyring *secrets.Keyring
+	if cfg.Secrets.MasterKey != "" {
+		if keyring, err = secrets.NewKeyring(cfg.Secrets.MasterKey); err != nil {
+			logger.Fatal("Failed to load secrets master key", zap.Error(err))
+		}
+	}
+
+	// Initialize notifiers. The API validates channels and queues notifications
+	// (e.g. incident acknowledgements), the worker delivers them.
+	dispatcher := notifications.NewDispatcher(notifications.NewNotifiers(cfg.Notifications), metricsCollector, secrets.NewResolver(repo, keyring), logger, cfg.Notifications.SendTimeout)
+	templates := notifications.NewTemplateRenderer(repo, cfg.Notifications.DashboardURL, logger)
+	outbox := notifications.NewOutbox(repo, dispatcher, templates, logger, cfg.Notifications.Outbox)
+
 	// Setup Gin
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -68,7 +85,7 @@ func main() {
 	r.Use(middleware.CORS())
 
 	// Setup handlers
-	h := handlers.NewHandler(repo, metricsCollector, keycloakClient, logger)
+	h := handlers.NewHandler(repo, metricsCollector, keycloakClient, dispatcher, outbox, templates, keyring, logger)
 
 	// Setup routes
 	api.SetupRoutes(r, h, keycloakClient)
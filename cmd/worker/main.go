@@ -11,8 +11,11 @@ import (
 	"github.com/leozw/uptime-guardian/internal/checks"
 	"github.com/leozw/uptime-guardian/internal/config"
 	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/escalation"
 	"github.com/leozw/uptime-guardian/internal/metrics"
+	"github.com/leozw/uptime-guardian/internal/notifications"
 	"github.com/leozw/uptime-guardian/internal/scheduler"
+	"github.com/leozw/uptime-guardian/internal/secrets"
 	"go.uber.org/zap"
 )
 
@@ -43,21 +46,48 @@ func main() {
 	// Initialize metrics collector
 	metricsCollector := metrics.NewCollector(cfg.Mimir)
 
+	// Initialize secrets, resolved into monitor and channel configs before use
+	var keyring *secrets.Keyring
+	if cfg.Secrets.MasterKey != "" {
+		if keyring, err = secrets.NewKeyring(cfg.Secrets.MasterKey); err != nil {
+			logger.Fatal("Failed to load secrets master key", zap.Error(err))
+		}
+	}
+	secretResolver := secrets.NewResolver(repo, keyring)
+
 	// Initialize check runners
 	checkRunners := map[string]checks.Runner{
-		"http":   checks.NewHTTPChecker(),
-		"ssl":    checks.NewSSLChecker(),
-		"dns":    checks.NewDNSChecker(),
-		"domain": checks.NewDomainChecker(),
+		"http":      checks.NewHTTPChecker(),
+		"ssl":       checks.NewSSLChecker(),
+		"dns":       checks.NewDNSChecker(),
+		"domain":    checks.NewDomainChecker(),
+		"tcp":       checks.NewTCPChecker(),
+		"ping":      checks.NewPingChecker(),
+		"grpc":      checks.NewGRPCChecker(),
+		"heartbeat": checks.NewHeartbeatChecker(repo),
+		"http_flow": checks.NewHTTPFlowChecker(),
 	}
 
+	// Initialize notifiers
+	notifiers := notifications.NewNotifiers(cfg.Notifications)
+	dispatcher := notifications.NewDispatcher(notifiers, metricsCollector, secretResolver, logger, cfg.Notifications.SendTimeout)
+	templates := notifications.NewTemplateRenderer(repo, cfg.Notifications.DashboardURL, logger)
+	outbox := notifications.NewOutbox(repo, dispatcher, templates, logger, cfg.Notifications.Outbox)
+	escalations := escalation.NewService(repo, outbox, logger, cfg.Notifications.Escalation)
+
 	// Initialize scheduler
-	sched := scheduler.NewScheduler(repo, metricsCollector, checkRunners, logger, cfg)
+	sched := scheduler.NewScheduler(repo, metricsCollector, checkRunners, outbox, secretResolver, logger, cfg)
 
 	// Start scheduler
 	ctx, cancel := context.WithCancel(context.Background())
 	go sched.Start(ctx)
 
+	// Start notification delivery
+	go outbox.Start(ctx)
+
+	// Start escalation of unacknowledged incidents
+	go escalations.Start(ctx)
+
 	// Start metrics exporter
 	go metricsCollector.StartRemoteWrite(ctx)
 
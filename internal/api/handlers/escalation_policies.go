@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+	"go.uber.org/zap"
+)
+
+const maxEscalationSteps = 10
+
+type EscalationPolicyRequest struct {
+	Name        string              `json:"name" binding:"required,min=1,max=255"`
+	Description string              `json:"description"`
+	Steps       []db.EscalationStep `json:"steps" binding:"required,min=1"`
+}
+
+func (h *Handler) ListEscalationPolicies(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	policies, err := h.repo.GetEscalationPoliciesByTenant(tenantID)
+	if err != nil {
+		h.logger.Error("Failed to list escalation policies", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policies": redactEscalationPolicies(policies)})
+}
+
+func (h *Handler) GetEscalationPolicy(c *gin.Context) {
+	policyID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	policy, err := h.repo.GetEscalationPolicy(policyID, tenantID)
+	if err != nil {
+		if err.Error() == "escalation policy not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Escalation policy not found"})
+			return
+		}
+		h.logger.Error("Failed to get escalation policy", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactEscalationPolicy(policy))
+}
+
+func (h *Handler) CreateEscalationPolicy(c *gin.Context) {
+	var req EscalationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+
+	if err := h.validateEscalationSteps(tenantID, req.Steps); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	policy := &db.EscalationPolicy{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		Name:        req.Name,
+		Description: req.Description,
+		Steps:       req.Steps,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := h.repo.CreateEscalationPolicy(policy); err != nil {
+		h.logger.Error("Failed to create escalation policy", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create escalation policy"})
+		return
+	}
+
+	h.logger.Info("Escalation policy created",
+		zap.String("escalation_policy_id", policy.ID),
+		zap.String("tenant_id", tenantID),
+	)
+
+	c.JSON(http.StatusCreated, redactEscalationPolicy(policy))
+}
+
+// UpdateEscalationPolicy replaces the policy. Incidents already escalating continue
+// from their current step with the new steps.
+func (h *Handler) UpdateEscalationPolicy(c *gin.Context) {
+	policyID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	policy, err := h.repo.GetEscalationPolicy(policyID, tenantID)
+	if err != nil {
+		if err.Error() == "escalation policy not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Escalation policy not found"})
+			return
+		}
+		h.logger.Error("Failed to get escalation policy", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req EscalationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var steps []db.EscalationStep
+	if err := secrets.Restore(req.Steps, policy.Steps, &steps); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validateEscalationSteps(tenantID, steps); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy.Name = req.Name
+	policy.Description = req.Description
+	policy.Steps = steps
+	policy.UpdatedAt = time.Now()
+
+	if err := h.repo.UpdateEscalationPolicy(policy); err != nil {
+		h.logger.Error("Failed to update escalation policy", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update escalation policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactEscalationPolicy(policy))
+}
+
+func (h *Handler) DeleteEscalationPolicy(c *gin.Context) {
+	policyID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	if _, err := h.repo.GetEscalationPolicy(policyID, tenantID); err != nil {
+		if err.Error() == "escalation policy not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Escalation policy not found"})
+			return
+		}
+		h.logger.Error("Failed to get escalation policy", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	references, err := h.repo.CountEscalationPolicyReferences(policyID, tenantID)
+	if err != nil {
+		h.logger.Error("Failed to count escalation policy references", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if references > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Escalation policy is still in use",
+			"references": references,
+		})
+		return
+	}
+
+	if err := h.repo.DeleteEscalationPolicy(policyID, tenantID); err != nil {
+		h.logger.Error("Failed to delete escalation policy", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete escalation policy"})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *Handler) validateEscalationSteps(tenantID string, steps []db.EscalationStep) error {
+	if len(steps) > maxEscalationSteps {
+		return fmt.Errorf("an escalation policy has at most %d steps", maxEscalationSteps)
+	}
+	for i, step := range steps {
+		if step.DelayMinutes < 0 || step.DelayMinutes > 1440 {
+			return fmt.Errorf("step %d: delay_minutes must be between 0 and 1440", i)
+		}
+		if len(step.Channels) == 0 {
+			return fmt.Errorf("step %d: at least one channel is required", i)
+		}
+		if err := h.validateNotificationChannels(tenantID, step.Channels); err != nil {
+			return fmt.Errorf("step %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -3,22 +3,32 @@ package handlers
 import (
 	"github.com/leozw/uptime-guardian/internal/db"
 	"github.com/leozw/uptime-guardian/internal/metrics"
+	"github.com/leozw/uptime-guardian/internal/notifications"
+	"github.com/leozw/uptime-guardian/internal/secrets"
 	"github.com/leozw/uptime-guardian/pkg/keycloak"
 	"go.uber.org/zap"
 )
 
 type Handler struct {
-	repo     *db.Repository
-	metrics  *metrics.Collector
-	keycloak *keycloak.Client
-	logger   *zap.Logger
+	repo       *db.Repository
+	metrics    *metrics.Collector
+	keycloak   *keycloak.Client
+	dispatcher *notifications.Dispatcher
+	outbox     *notifications.Outbox
+	templates  *notifications.TemplateRenderer
+	keyring    *secrets.Keyring
+	logger     *zap.Logger
 }
 
-func NewHandler(repo *db.Repository, metrics *metrics.Collector, keycloak *keycloak.Client, logger *zap.Logger) *Handler {
+func NewHandler(repo *db.Repository, metrics *metrics.Collector, keycloak *keycloak.Client, dispatcher *notifications.Dispatcher, outbox *notifications.Outbox, templates *notifications.TemplateRenderer, keyring *secrets.Keyring, logger *zap.Logger) *Handler {
 	return &Handler{
-		repo:     repo,
-		metrics:  metrics,
-		keycloak: keycloak,
-		logger:   logger,
+		repo:       repo,
+		metrics:    metrics,
+		keycloak:   keycloak,
+		dispatcher: dispatcher,
+		outbox:     outbox,
+		templates:  templates,
+		keyring:    keyring,
+		logger:     logger,
 	}
 }
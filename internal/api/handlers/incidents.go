@@ -60,7 +60,7 @@ func (h *Handler) AcknowledgeIncident(c *gin.Context) {
 	userEmail := c.GetString("user_email")
 
 	// Pass metrics to incident service
-	incidentService := incidents.NewService(h.repo, h.logger, h.metrics)
+	incidentService := incidents.NewService(h.repo, h.logger, h.metrics, h.outbox)
 
 	if err := incidentService.AcknowledgeIncident(incidentID, tenantID, userEmail); err != nil {
 		h.logger.Error("Failed to acknowledge incident", zap.Error(err))
@@ -86,7 +86,7 @@ func (h *Handler) AddIncidentComment(c *gin.Context) {
 	}
 
 	// Pass metrics to incident service
-	incidentService := incidents.NewService(h.repo, h.logger, h.metrics)
+	incidentService := incidents.NewService(h.repo, h.logger, h.metrics, h.outbox)
 
 	if err := incidentService.AddIncidentComment(incidentID, tenantID, userEmail, req.Comment); err != nil {
 		h.logger.Error("Failed to add incident comment", zap.Error(err))
@@ -128,7 +128,7 @@ func (h *Handler) GetMonitorSLA(c *gin.Context) {
 	slo, _ := h.repo.GetMonitorSLO(monitorID)
 
 	c.JSON(http.StatusOK, gin.H{
-		"monitor":     monitor,
+		"monitor":     redactMonitor(monitor),
 		"slo":         slo,
 		"current_sla": currentSLA,
 		"history":     history,
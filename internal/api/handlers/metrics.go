@@ -243,7 +243,7 @@ func (h *Handler) GetMonitorMetrics(c *gin.Context) {
 	}
 
 	response := gin.H{
-		"monitor":        monitor,
+		"monitor":        redactMonitor(monitor),
 		"current_status": status,
 		"sla": gin.H{
 			"current":           currentSLA,
@@ -363,7 +363,7 @@ func (h *Handler) GetMonitorsPerformance(c *gin.Context) {
 			"monitor_id":               monitor.ID,
 			"monitor_name":             monitor.Name,
 			"monitor_type":             monitor.Type,
-			"target":                   monitor.Target,
+			"target":                   redactTarget(monitor.Target),
 			"average_response_time_ms": avgResponseTime,
 			"total_checks":             len(checkHistory),
 			"successful_checks":        successfulChecks,
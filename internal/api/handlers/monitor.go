@@ -1,20 +1,27 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
 	"net/http"
+	"regexp"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/leozw/uptime-guardian/internal/checks"
 	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/notifications"
 	"go.uber.org/zap"
 )
 
 type CreateMonitorRequest struct {
 	Name             string                 `json:"name" binding:"required,min=1,max=255"`
-	Type             string                 `json:"type" binding:"required,oneof=http ssl dns domain"`
-	Target           string                 `json:"target" binding:"required"`
+	Type             string                 `json:"type" binding:"required,oneof=http ssl dns domain tcp ping grpc heartbeat http_flow"`
+	Target           string                 `json:"target" binding:"required_unless=Type heartbeat"`
 	Enabled          *bool                  `json:"enabled" binding:"required"`
 	Interval         int                    `json:"interval" binding:"required,min=30,max=86400"`
 	Timeout          int                    `json:"timeout" binding:"required,min=1,max=60"`
@@ -49,7 +56,7 @@ func (h *Handler) CreateMonitor(c *gin.Context) {
 	}
 
 	// Validate monitor config based on type
-	if err := h.validateMonitorConfig(req.Type, req.Config); err != nil {
+	if err := h.validateMonitorConfig(tenantID, req.Type, req.Target, req.Config); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -70,8 +77,21 @@ func (h *Handler) CreateMonitor(c *gin.Context) {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
+	if monitor.Type == db.MonitorTypeHeartbeat {
+		token, err := newHeartbeatToken()
+		if err != nil {
+			h.logger.Error("Failed to generate heartbeat token", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create monitor"})
+			return
+		}
+		monitor.HeartbeatToken = &token
+	}
 
 	if req.NotificationConf != nil {
+		if err := h.validateNotificationConfig(tenantID, req.NotificationConf); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		monitor.NotificationConf = *req.NotificationConf
 	}
 
@@ -87,7 +107,7 @@ func (h *Handler) CreateMonitor(c *gin.Context) {
 		zap.String("user", userEmail),
 	)
 
-	c.JSON(http.StatusCreated, monitor)
+	c.JSON(http.StatusCreated, redactMonitor(monitor))
 }
 
 func (h *Handler) GetMonitor(c *gin.Context) {
@@ -105,7 +125,7 @@ func (h *Handler) GetMonitor(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, monitor)
+	c.JSON(http.StatusOK, redactMonitor(monitor))
 }
 
 func (h *Handler) ListMonitors(c *gin.Context) {
@@ -134,7 +154,7 @@ func (h *Handler) ListMonitors(c *gin.Context) {
 	total, _ := h.repo.CountMonitorsByTenant(tenantID)
 
 	c.JSON(http.StatusOK, gin.H{
-		"monitors": monitors,
+		"monitors": redactMonitors(monitors),
 		"pagination": gin.H{
 			"page":  page,
 			"limit": limit,
@@ -164,6 +184,15 @@ func (h *Handler) UpdateMonitor(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := restoreMonitorRequest(&req, monitor); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validateMonitorConfig(tenantID, req.Type, req.Target, req.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Update fields
 	monitor.Name = req.Name
@@ -176,7 +205,24 @@ func (h *Handler) UpdateMonitor(c *gin.Context) {
 	monitor.Config = req.Config
 	monitor.Tags = db.JSONB(req.Tags)
 
+	// Keep the ping URL across updates so jobs do not need to be reconfigured
+	if monitor.Type != db.MonitorTypeHeartbeat {
+		monitor.HeartbeatToken = nil
+	} else if monitor.HeartbeatToken == nil {
+		token, err := newHeartbeatToken()
+		if err != nil {
+			h.logger.Error("Failed to generate heartbeat token", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update monitor"})
+			return
+		}
+		monitor.HeartbeatToken = &token
+	}
+
 	if req.NotificationConf != nil {
+		if err := h.validateNotificationConfig(tenantID, req.NotificationConf); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		monitor.NotificationConf = *req.NotificationConf
 	}
 
@@ -186,7 +232,7 @@ func (h *Handler) UpdateMonitor(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, monitor)
+	c.JSON(http.StatusOK, redactMonitor(monitor))
 }
 
 func (h *Handler) DeleteMonitor(c *gin.Context) {
@@ -273,7 +319,162 @@ func (h *Handler) GetMonitorHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"history": history})
 }
 
-func (h *Handler) validateMonitorConfig(monitorType string, config db.MonitorConfig) error {
-	// TODO: Implement validation based on monitor type
+func (h *Handler) validateMonitorConfig(tenantID, monitorType, target string, config db.MonitorConfig) error {
+	if err := h.validateSecretReferences(tenantID, []interface{}{target, config}); err != nil {
+		return err
+	}
+	if err := checks.ValidateTLSConfig(config.TLS); err != nil {
+		return fmt.Errorf("invalid tls config: %v", err)
+	}
+	if err := checks.ValidateProxyURL(config.ProxyURL); err != nil {
+		return fmt.Errorf("invalid proxy_url: %v", err)
+	}
+
+	switch db.MonitorType(monitorType) {
+	case db.MonitorTypeHTTP:
+		if err := checks.ValidateAssertions(config.Assertions); err != nil {
+			return err
+		}
+		if err := checks.ValidateOAuth2Config(config.OAuth2); err != nil {
+			return fmt.Errorf("invalid oauth2 config: %v", err)
+		}
+	case db.MonitorTypeTCP:
+		host, port, err := net.SplitHostPort(target)
+		if err != nil || host == "" {
+			return fmt.Errorf("target must be host:port for tcp monitors")
+		}
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("invalid port %q", port)
+		}
+		if config.TCPExpectRegex != "" {
+			if _, err := regexp.Compile(config.TCPExpectRegex); err != nil {
+				return fmt.Errorf("invalid tcp_expect_regex: %v", err)
+			}
+		}
+	case db.MonitorTypeGRPC:
+		if _, _, err := net.SplitHostPort(target); err != nil {
+			return fmt.Errorf("target must be host:port for grpc monitors")
+		}
+	case db.MonitorTypeHeartbeat:
+		if config.HeartbeatGracePeriod < 0 || config.HeartbeatGracePeriod > 86400 {
+			return fmt.Errorf("heartbeat_grace_period must be between 0 and 86400 seconds")
+		}
+	case db.MonitorTypeHTTPFlow:
+		if len(config.Steps) == 0 || len(config.Steps) > 20 {
+			return fmt.Errorf("http_flow monitors need between 1 and 20 steps")
+		}
+		for i, step := range config.Steps {
+			if step.URL == "" {
+				return fmt.Errorf("step %d: url is required", i+1)
+			}
+			if err := checks.ValidateAssertions(step.Assertions); err != nil {
+				return fmt.Errorf("step %d: %v", i+1, err)
+			}
+			for _, extract := range step.Extract {
+				if !flowVariableName.MatchString(extract.Variable) {
+					return fmt.Errorf("step %d: invalid variable name %q", i+1, extract.Variable)
+				}
+				switch extract.From {
+				case db.ExtractFromJSON:
+					if err := checks.ValidateJSONPath(extract.Path); err != nil {
+						return fmt.Errorf("step %d: %v", i+1, err)
+					}
+				case db.ExtractFromHeader:
+					if extract.Path == "" {
+						return fmt.Errorf("step %d: header name is required to extract %s", i+1, extract.Variable)
+					}
+				case db.ExtractFromRegex:
+					if _, err := regexp.Compile(extract.Path); err != nil {
+						return fmt.Errorf("step %d: invalid regex for %s: %v", i+1, extract.Variable, err)
+					}
+				default:
+					return fmt.Errorf("step %d: extract from must be json, header or regex", i+1)
+				}
+			}
+		}
+	case db.MonitorTypePing:
+		if config.PingCount < 0 || config.PingCount > 20 {
+			return fmt.Errorf("ping_count must be between 1 and 20, or 0 for the default of 5")
+		}
+		if config.PingMaxPacketLoss < 0 || config.PingMaxPacketLoss >= 100 {
+			return fmt.Errorf("ping_max_packet_loss must be a percentage below 100")
+		}
+		if config.PingMaxLatencyMs < 0 {
+			return fmt.Errorf("ping_max_latency_ms must not be negative")
+		}
+	}
+	return nil
+}
+
+var flowVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// newHeartbeatToken returns the secret part of a heartbeat monitor's ping URL
+func newHeartbeatToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// validateNotificationConfig checks the channels and escalation policy of a monitor or group
+func (h *Handler) validateNotificationConfig(tenantID string, conf *db.NotificationConfig) error {
+	if err := h.validateNotificationChannels(tenantID, conf.Channels); err != nil {
+		return err
+	}
+	if conf.EscalationPolicyID != "" {
+		if _, err := h.repo.GetEscalationPolicy(conf.EscalationPolicyID, tenantID); err != nil {
+			return fmt.Errorf("escalation policy %s not found", conf.EscalationPolicyID)
+		}
+	}
+	return nil
+}
+
+// validateNotificationChannels checks inline channels against their notifier's config
+// schema and that referenced channels exist. References are reduced to their ID so
+// the stored channel stays the single source of its config.
+func (h *Handler) validateNotificationChannels(tenantID string, channels []db.NotificationChannel) error {
+	var ids []string
+	for _, channel := range channels {
+		if channel.ID != "" {
+			ids = append(ids, channel.ID)
+		}
+	}
+
+	stored, err := h.repo.GetNotificationChannelsByIDs(tenantID, ids)
+	if err != nil {
+		return err
+	}
+
+	for i, channel := range channels {
+		if channel.ID != "" {
+			if _, ok := stored[channel.ID]; !ok {
+				return fmt.Errorf("notification channel %d: channel %s not found", i, channel.ID)
+			}
+			channels[i] = db.NotificationChannel{ID: channel.ID}
+			continue
+		}
+		if err := h.dispatcher.Validate(channel); err != nil {
+			return fmt.Errorf("notification channel %d: %w", i, err)
+		}
+		if err := h.validateOnCallTarget(tenantID, channel); err != nil {
+			return fmt.Errorf("notification channel %d: %w", i, err)
+		}
+		if err := h.validateSecretReferences(tenantID, channel.Config); err != nil {
+			return fmt.Errorf("notification channel %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateOnCallTarget checks that an on-call channel pages an existing schedule
+func (h *Handler) validateOnCallTarget(tenantID string, channel db.NotificationChannel) error {
+	if channel.Type != notifications.ChannelTypeOnCall {
+		return nil
+	}
+	scheduleID := notifications.OnCallScheduleID(channel)
+	if _, err := h.repo.GetOnCallSchedule(scheduleID, tenantID); err != nil {
+		return fmt.Errorf("on-call schedule %s not found", scheduleID)
+	}
 	return nil
 }
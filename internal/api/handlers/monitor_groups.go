@@ -9,6 +9,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/groups"
+	"github.com/leozw/uptime-guardian/internal/secrets"
 	"go.uber.org/zap"
 )
 
@@ -129,6 +131,10 @@ func (h *Handler) CreateMonitorGroup(c *gin.Context) {
 	}
 
 	if req.NotificationConf != nil {
+		if err := h.validateNotificationConfig(tenantID, req.NotificationConf); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		group.NotificationConf = *req.NotificationConf
 	}
 
@@ -229,7 +235,7 @@ func (h *Handler) CreateMonitorGroup(c *gin.Context) {
 		)
 	}
 
-	c.JSON(http.StatusCreated, group)
+	c.JSON(http.StatusCreated, redactGroup(group))
 }
 
 func (h *Handler) GetMonitorGroup(c *gin.Context) {
@@ -269,7 +275,7 @@ func (h *Handler) GetMonitorGroup(c *gin.Context) {
 		group.Status = h.calculateGroupStatus(group)
 	}
 
-	c.JSON(http.StatusOK, group)
+	c.JSON(http.StatusOK, redactGroup(group))
 }
 
 func (h *Handler) ListMonitorGroups(c *gin.Context) {
@@ -319,7 +325,7 @@ func (h *Handler) ListMonitorGroups(c *gin.Context) {
 	total, _ := h.repo.CountMonitorGroupsByTenant(tenantID)
 
 	c.JSON(http.StatusOK, gin.H{
-		"groups": groups,
+		"groups": redactGroups(groups),
 		"pagination": gin.H{
 			"page":  page,
 			"limit": limit,
@@ -358,7 +364,16 @@ func (h *Handler) UpdateMonitorGroup(c *gin.Context) {
 	group.UpdatedAt = time.Now()
 
 	if req.NotificationConf != nil {
-		group.NotificationConf = *req.NotificationConf
+		var conf db.NotificationConfig
+		if err := secrets.Restore(req.NotificationConf, group.NotificationConf, &conf); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := h.validateNotificationConfig(tenantID, &conf); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		group.NotificationConf = conf
 	}
 
 	if err := h.repo.UpdateMonitorGroup(group); err != nil {
@@ -367,7 +382,7 @@ func (h *Handler) UpdateMonitorGroup(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, group)
+	c.JSON(http.StatusOK, redactGroup(group))
 }
 
 func (h *Handler) DeleteMonitorGroup(c *gin.Context) {
@@ -490,6 +505,28 @@ func (h *Handler) GetMonitorGroupIncidents(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"incidents": incidents})
 }
 
+// AcknowledgeGroupIncident stops the escalation of a group incident
+func (h *Handler) AcknowledgeGroupIncident(c *gin.Context) {
+	groupID := c.Param("id")
+	incidentID := c.Param("incident_id")
+	tenantID := c.GetString("tenant_id")
+	userEmail := c.GetString("user_email")
+
+	groupService := groups.NewService(h.repo, h.logger, h.metrics, h.outbox)
+
+	if err := groupService.AcknowledgeGroupIncident(groupID, incidentID, tenantID, userEmail); err != nil {
+		if err.Error() == "group incident not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Group incident not found"})
+			return
+		}
+		h.logger.Error("Failed to acknowledge group incident", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Incident acknowledged"})
+}
+
 func (h *Handler) SetMonitorGroupSLO(c *gin.Context) {
 	groupID := c.Param("id")
 	tenantID := c.GetString("tenant_id")
@@ -549,6 +586,11 @@ func (h *Handler) CreateGroupAlertRule(c *gin.Context) {
 		return
 	}
 
+	if err := h.validateNotificationChannels(tenantID, req.NotificationChannels); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Validate threshold value for certain conditions
 	if req.TriggerCondition == db.TriggerHealthScoreBelow || req.TriggerCondition == db.TriggerPercentageDown {
 		if req.ThresholdValue == nil || *req.ThresholdValue < 0 || *req.ThresholdValue > 100 {
@@ -580,7 +622,7 @@ func (h *Handler) CreateGroupAlertRule(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, rule)
+	c.JSON(http.StatusCreated, redactAlertRule(rule))
 }
 
 // Helper method to calculate group status on the fly
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/notifications"
+	"go.uber.org/zap"
+)
+
+type NotificationTemplateRequest struct {
+	ChannelID *string `json:"channel_id"`
+	Event     *string `json:"event" binding:"omitempty,oneof=down degraded reminder recovery acknowledged test"`
+	Title     string  `json:"title"`
+	Body      string  `json:"body"`
+}
+
+// TemplatePreviewRequest renders either a stored template or the given title/body.
+// The message is built from a historical check result, a monitor with a sample
+// result, or entirely from sample data.
+type TemplatePreviewRequest struct {
+	TemplateID    string `json:"template_id"`
+	Title         string `json:"title"`
+	Body          string `json:"body"`
+	Event         string `json:"event" binding:"omitempty,oneof=down degraded reminder recovery acknowledged test"`
+	MonitorID     string `json:"monitor_id"`
+	CheckResultID string `json:"check_result_id"`
+}
+
+func (h *Handler) ListNotificationTemplates(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	templates, err := h.repo.GetNotificationTemplatesByTenant(tenantID)
+	if err != nil {
+		h.logger.Error("Failed to list notification templates", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+func (h *Handler) CreateNotificationTemplate(c *gin.Context) {
+	var req NotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+
+	if err := h.validateNotificationTemplate(tenantID, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	tmpl := &db.NotificationTemplate{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		ChannelID: req.ChannelID,
+		Event:     req.Event,
+		Title:     req.Title,
+		Body:      req.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := h.repo.CreateNotificationTemplate(tmpl); err != nil {
+		if err.Error() == "notification template already exists" {
+			c.JSON(http.StatusConflict, gin.H{"error": "A template for this channel and event already exists"})
+			return
+		}
+		h.logger.Error("Failed to create notification template", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create notification template"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tmpl)
+}
+
+func (h *Handler) UpdateNotificationTemplate(c *gin.Context) {
+	templateID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	tmpl, err := h.repo.GetNotificationTemplate(templateID, tenantID)
+	if err != nil {
+		if err.Error() == "notification template not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification template not found"})
+			return
+		}
+		h.logger.Error("Failed to get notification template", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req NotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validateNotificationTemplate(tenantID, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tmpl.ChannelID = req.ChannelID
+	tmpl.Event = req.Event
+	tmpl.Title = req.Title
+	tmpl.Body = req.Body
+	tmpl.UpdatedAt = time.Now()
+
+	if err := h.repo.UpdateNotificationTemplate(tmpl); err != nil {
+		if err.Error() == "notification template already exists" {
+			c.JSON(http.StatusConflict, gin.H{"error": "A template for this channel and event already exists"})
+			return
+		}
+		h.logger.Error("Failed to update notification template", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tmpl)
+}
+
+func (h *Handler) DeleteNotificationTemplate(c *gin.Context) {
+	templateID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	if err := h.repo.DeleteNotificationTemplate(templateID, tenantID); err != nil {
+		if err.Error() == "notification template not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification template not found"})
+			return
+		}
+		h.logger.Error("Failed to delete notification template", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification template"})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// PreviewNotificationTemplate renders a template without sending anything
+func (h *Handler) PreviewNotificationTemplate(c *gin.Context) {
+	var req TemplatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+
+	title, body := req.Title, req.Body
+	event := notifications.EventType(req.Event)
+	if req.TemplateID != "" {
+		tmpl, err := h.repo.GetNotificationTemplate(req.TemplateID, tenantID)
+		if err != nil {
+			if err.Error() == "notification template not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Notification template not found"})
+				return
+			}
+			h.logger.Error("Failed to get notification template", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+		title, body = tmpl.Title, tmpl.Body
+		if event == "" && tmpl.Event != nil {
+			event = notifications.EventType(*tmpl.Event)
+		}
+	}
+
+	var monitor *db.Monitor
+	var result *db.CheckResult
+	var err error
+
+	if req.CheckResultID != "" {
+		result, err = h.repo.GetCheckResult(req.CheckResultID, tenantID)
+		if err != nil {
+			if err.Error() == "check result not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Check result not found"})
+				return
+			}
+			h.logger.Error("Failed to get check result", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+		req.MonitorID = result.MonitorID
+	}
+
+	if req.MonitorID != "" {
+		monitor, err = h.repo.GetMonitor(req.MonitorID, tenantID)
+		if err != nil {
+			if err.Error() == "monitor not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Monitor not found"})
+				return
+			}
+			h.logger.Error("Failed to get monitor", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+	}
+
+	if event == "" {
+		event = notifications.EventDown
+		if result != nil {
+			if result.Status == db.StatusUp {
+				event = notifications.EventRecovery
+			} else {
+				event = notifications.EventForStatus(result.Status)
+			}
+		}
+	}
+
+	msg := notifications.SampleMessage(tenantID, event, monitor, result)
+
+	renderedTitle, renderedBody, err := h.templates.Render(title, body, msg)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	msg.CustomTitle = renderedTitle
+	msg.CustomBody = renderedBody
+
+	c.JSON(http.StatusOK, gin.H{
+		"event":  event,
+		"title":  notifications.Title(msg),
+		"body":   notifications.Summary(msg),
+		"fields": notifications.Fields(msg),
+		"sample": req.CheckResultID == "",
+	})
+}
+
+func (h *Handler) validateNotificationTemplate(tenantID string, req *NotificationTemplateRequest) error {
+	if req.ChannelID != nil && *req.ChannelID == "" {
+		req.ChannelID = nil
+	}
+	if req.Event != nil && *req.Event == "" {
+		req.Event = nil
+	}
+
+	if req.Title == "" && req.Body == "" {
+		return fmt.Errorf("title or body is required")
+	}
+	if _, err := notifications.ParseTemplate("title", req.Title); err != nil {
+		return err
+	}
+	if _, err := notifications.ParseTemplate("body", req.Body); err != nil {
+		return err
+	}
+
+	if req.ChannelID != nil {
+		if _, err := h.repo.GetNotificationChannel(*req.ChannelID, tenantID); err != nil {
+			return fmt.Errorf("notification channel %s not found", *req.ChannelID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/notifications"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+	"go.uber.org/zap"
+)
+
+type NotificationChannelRequest struct {
+	Name              string              `json:"name" binding:"required,min=1,max=255"`
+	Type              string              `json:"type" binding:"required"`
+	Config            db.JSONB            `json:"config" binding:"required"`
+	Enabled           *bool               `json:"enabled" binding:"required"`
+	AggregationWindow int                 `json:"aggregation_window" binding:"min=0,max=3600"`
+	Digest            string              `json:"digest" binding:"omitempty,oneof=hourly daily"`
+	MinSeverity       string              `json:"min_severity" binding:"omitempty,oneof=critical warning info"`
+	Schedule          *db.ChannelSchedule `json:"schedule"`
+}
+
+// TestNotificationRequest tests either a stored channel or an unsaved type/config
+type TestNotificationRequest struct {
+	ChannelID string   `json:"channel_id"`
+	Type      string   `json:"type"`
+	Config    db.JSONB `json:"config"`
+}
+
+func (h *Handler) ListNotificationChannels(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	channels, err := h.repo.GetNotificationChannelsByTenant(tenantID)
+	if err != nil {
+		h.logger.Error("Failed to list notification channels", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"channels": redactStoredChannels(channels)})
+}
+
+func (h *Handler) CreateNotificationChannel(c *gin.Context) {
+	var req NotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	now := time.Now()
+
+	channel := &db.NotificationChannel{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		Name:      req.Name,
+		Type:      req.Type,
+		Config:    req.Config,
+		Enabled:   *req.Enabled,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+
+		AggregationWindow: req.AggregationWindow,
+		Digest:            req.Digest,
+		MinSeverity:       req.MinSeverity,
+		Schedule:          req.Schedule,
+	}
+
+	if err := h.dispatcher.Validate(*channel); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.validateOnCallTarget(tenantID, *channel); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.validateSecretReferences(tenantID, channel.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.CreateNotificationChannel(channel); err != nil {
+		h.logger.Error("Failed to create notification channel", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create notification channel"})
+		return
+	}
+
+	h.logger.Info("Notification channel created",
+		zap.String("channel_id", channel.ID),
+		zap.String("channel_type", channel.Type),
+		zap.String("tenant_id", tenantID),
+	)
+
+	c.JSON(http.StatusCreated, redactChannel(*channel))
+}
+
+func (h *Handler) UpdateNotificationChannel(c *gin.Context) {
+	channelID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	channel, err := h.repo.GetNotificationChannel(channelID, tenantID)
+	if err != nil {
+		if err.Error() == "notification channel not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification channel not found"})
+			return
+		}
+		h.logger.Error("Failed to get notification channel", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req NotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var config db.JSONB
+	if err := secrets.Restore(req.Config, channel.Config, &config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	channel.Name = req.Name
+	channel.Type = req.Type
+	channel.Config = config
+	channel.Enabled = *req.Enabled
+	channel.AggregationWindow = req.AggregationWindow
+	channel.Digest = req.Digest
+	channel.MinSeverity = req.MinSeverity
+	channel.Schedule = req.Schedule
+	channel.UpdatedAt = &now
+
+	if err := h.dispatcher.Validate(*channel); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.validateOnCallTarget(tenantID, *channel); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.validateSecretReferences(tenantID, channel.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateNotificationChannel(channel); err != nil {
+		h.logger.Error("Failed to update notification channel", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification channel"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactChannel(*channel))
+}
+
+// DeleteNotificationChannel refuses to delete channels that are still referenced,
+// e.g. by monitors, escalation policies or on-call members, so alerts are not silently dropped
+func (h *Handler) DeleteNotificationChannel(c *gin.Context) {
+	channelID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	if _, err := h.repo.GetNotificationChannel(channelID, tenantID); err != nil {
+		if err.Error() == "notification channel not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Notification channel not found"})
+			return
+		}
+		h.logger.Error("Failed to get notification channel", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	references, err := h.repo.CountNotificationChannelReferences(channelID, tenantID)
+	if err != nil {
+		h.logger.Error("Failed to count notification channel references", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if references > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Notification channel is still in use",
+			"references": references,
+		})
+		return
+	}
+
+	if err := h.repo.DeleteNotificationChannel(channelID, tenantID); err != nil {
+		h.logger.Error("Failed to delete notification channel", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete notification channel"})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// TestNotification sends a test message synchronously and reports the outcome.
+// Integrations that open incidents (PagerDuty, Opsgenie) are resolved right after.
+func (h *Handler) TestNotification(c *gin.Context) {
+	var req TestNotificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+
+	var channel db.NotificationChannel
+	if req.ChannelID != "" {
+		stored, err := h.repo.GetNotificationChannel(req.ChannelID, tenantID)
+		if err != nil {
+			if err.Error() == "notification channel not found" {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Notification channel not found"})
+				return
+			}
+			h.logger.Error("Failed to get notification channel", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+		channel = *stored
+	} else {
+		if req.Type == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "channel_id or type is required"})
+			return
+		}
+		channel = db.NotificationChannel{Name: "Test", Type: req.Type, Config: req.Config, Enabled: true}
+		if err := h.dispatcher.Validate(channel); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if channel.Type == notifications.ChannelTypeOnCall {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "On-call channels are tested through the contact channels of the schedule's members"})
+		return
+	}
+
+	now := time.Now()
+	msg := &notifications.Message{
+		Event: notifications.EventTest,
+		Monitor: &db.Monitor{
+			ID:       "test",
+			TenantID: tenantID,
+			Name:     "Test notification",
+			Target:   channel.Name,
+		},
+		Incident: &db.Incident{
+			ID:               uuid.New().String(),
+			TenantID:         tenantID,
+			StartedAt:        now,
+			Severity:         "info",
+			NotificationRefs: make(db.JSONB),
+		},
+		SentAt: now,
+	}
+
+	h.templates.Apply(channel, msg)
+
+	start := time.Now()
+	err := h.dispatcher.Send(c.Request.Context(), channel, msg)
+	if err == nil && h.dispatcher.MirrorsLifecycle(channel.Type) {
+		msg.Event = notifications.EventRecovery
+		msg.CustomTitle, msg.CustomBody = "", ""
+		msg.Incident.ResolvedAt = &now
+		err = h.dispatcher.Send(c.Request.Context(), channel, msg)
+	}
+	latency := time.Since(start)
+
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{
+			"success":      false,
+			"channel_type": channel.Type,
+			"error":        err.Error(),
+			"latency_ms":   latency.Milliseconds(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"channel_type": channel.Type,
+		"latency_ms":   latency.Milliseconds(),
+	})
+}
+
+// ListNotificationDeliveries returns the notification outbox for auditing
+func (h *Handler) ListNotificationDeliveries(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	// Pagination
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	offset := (page - 1) * limit
+
+	// Filters
+	filters := &db.DeliveryFilters{
+		TenantID:    tenantID,
+		Status:      c.Query("status"),       // "pending", "delivered", "failed"
+		ChannelType: c.Query("channel_type"), // "slack", "email", ...
+		MonitorID:   c.Query("monitor_id"),
+		IncidentID:  c.Query("incident_id"),
+		Limit:       limit,
+		Offset:      offset,
+	}
+
+	deliveries, err := h.repo.GetNotificationDeliveries(filters)
+	if err != nil {
+		h.logger.Error("Failed to list notification deliveries", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	total, _ := h.repo.CountNotificationDeliveries(filters)
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+		"filters": gin.H{
+			"status":       filters.Status,
+			"channel_type": filters.ChannelType,
+			"monitor_id":   filters.MonitorID,
+			"incident_id":  filters.IncidentID,
+		},
+	})
+}
@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/notifications"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+	"go.uber.org/zap"
+)
+
+type OnCallScheduleRequest struct {
+	Name        string            `json:"name" binding:"required,min=1,max=255"`
+	Description string            `json:"description"`
+	Timezone    string            `json:"timezone"`
+	Members     []db.OnCallMember `json:"members" binding:"required,min=1"`
+	Layers      []db.OnCallLayer  `json:"layers" binding:"required,min=1"`
+}
+
+type OnCallOverrideRequest struct {
+	User     string    `json:"user" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+}
+
+func (h *Handler) ListOnCallSchedules(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	schedules, err := h.repo.GetOnCallSchedulesByTenant(tenantID)
+	if err != nil {
+		h.logger.Error("Failed to list on-call schedules", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedules": redactOnCallSchedules(schedules)})
+}
+
+// GetOnCallSchedule returns the schedule with its current and upcoming overrides
+func (h *Handler) GetOnCallSchedule(c *gin.Context) {
+	scheduleID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	schedule, err := h.repo.GetOnCallSchedule(scheduleID, tenantID)
+	if err != nil {
+		if err.Error() == "on-call schedule not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "On-call schedule not found"})
+			return
+		}
+		h.logger.Error("Failed to get on-call schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	now := time.Now()
+	schedule.Overrides, err = h.repo.GetOnCallOverrides(scheduleID, now, now.AddDate(1, 0, 0))
+	if err != nil {
+		h.logger.Error("Failed to get on-call overrides", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactOnCallSchedule(schedule))
+}
+
+func (h *Handler) CreateOnCallSchedule(c *gin.Context) {
+	var req OnCallScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tenantID := c.GetString("tenant_id")
+	now := time.Now()
+
+	schedule := &db.OnCallSchedule{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		Name:        req.Name,
+		Description: req.Description,
+		Timezone:    req.Timezone,
+		Members:     req.Members,
+		Layers:      req.Layers,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+
+	if err := h.validateOnCallSchedule(tenantID, schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.CreateOnCallSchedule(schedule); err != nil {
+		h.logger.Error("Failed to create on-call schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create on-call schedule"})
+		return
+	}
+
+	h.logger.Info("On-call schedule created",
+		zap.String("schedule_id", schedule.ID),
+		zap.String("tenant_id", tenantID),
+	)
+
+	c.JSON(http.StatusCreated, redactOnCallSchedule(schedule))
+}
+
+func (h *Handler) UpdateOnCallSchedule(c *gin.Context) {
+	scheduleID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	schedule, err := h.repo.GetOnCallSchedule(scheduleID, tenantID)
+	if err != nil {
+		if err.Error() == "on-call schedule not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "On-call schedule not found"})
+			return
+		}
+		h.logger.Error("Failed to get on-call schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req OnCallScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var members db.OnCallMembers
+	if err := secrets.Restore(req.Members, schedule.Members, &members); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule.Name = req.Name
+	schedule.Description = req.Description
+	schedule.Timezone = req.Timezone
+	schedule.Members = members
+	schedule.Layers = req.Layers
+	schedule.UpdatedAt = time.Now()
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+
+	if err := h.validateOnCallSchedule(tenantID, schedule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateOnCallSchedule(schedule); err != nil {
+		h.logger.Error("Failed to update on-call schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update on-call schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, redactOnCallSchedule(schedule))
+}
+
+// DeleteOnCallSchedule refuses to delete schedules that channels or escalation policies still page
+func (h *Handler) DeleteOnCallSchedule(c *gin.Context) {
+	scheduleID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	if _, err := h.repo.GetOnCallSchedule(scheduleID, tenantID); err != nil {
+		if err.Error() == "on-call schedule not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "On-call schedule not found"})
+			return
+		}
+		h.logger.Error("Failed to get on-call schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	references, err := h.repo.CountOnCallScheduleReferences(scheduleID, tenantID)
+	if err != nil {
+		h.logger.Error("Failed to count on-call schedule references", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if references > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "On-call schedule is still in use",
+			"references": references,
+		})
+		return
+	}
+
+	if err := h.repo.DeleteOnCallSchedule(scheduleID, tenantID); err != nil {
+		h.logger.Error("Failed to delete on-call schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete on-call schedule"})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// GetOnCall returns who is on call now, or at the time given by ?at= (RFC 3339)
+func (h *Handler) GetOnCall(c *gin.Context) {
+	scheduleID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "at must be an RFC 3339 time"})
+			return
+		}
+		at = parsed
+	}
+
+	schedule, err := h.repo.GetOnCallSchedule(scheduleID, tenantID)
+	if err != nil {
+		if err.Error() == "on-call schedule not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "On-call schedule not found"})
+			return
+		}
+		h.logger.Error("Failed to get on-call schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	overrides, err := h.repo.GetOnCallOverrides(scheduleID, at, at)
+	if err != nil {
+		h.logger.Error("Failed to get on-call overrides", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"schedule_id": scheduleID,
+		"at":          at,
+		"on_call":     notifications.WhoIsOnCall(schedule, overrides, at),
+	})
+}
+
+func (h *Handler) CreateOnCallOverride(c *gin.Context) {
+	scheduleID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+	userEmail := c.GetString("user_email")
+
+	schedule, err := h.repo.GetOnCallSchedule(scheduleID, tenantID)
+	if err != nil {
+		if err.Error() == "on-call schedule not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "On-call schedule not found"})
+			return
+		}
+		h.logger.Error("Failed to get on-call schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req OnCallOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ends_at must be after starts_at"})
+		return
+	}
+	// Only members have contact channels to be paged through
+	if schedule.Member(req.User) == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s is not a member of the schedule", req.User)})
+		return
+	}
+
+	// The columns have no time zone, so times are stored in UTC
+	override := &db.OnCallOverride{
+		ID:         uuid.New().String(),
+		ScheduleID: scheduleID,
+		User:       req.User,
+		StartsAt:   req.StartsAt.UTC(),
+		EndsAt:     req.EndsAt.UTC(),
+		CreatedBy:  userEmail,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := h.repo.CreateOnCallOverride(override); err != nil {
+		h.logger.Error("Failed to create on-call override", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create on-call override"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, override)
+}
+
+func (h *Handler) DeleteOnCallOverride(c *gin.Context) {
+	scheduleID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	if _, err := h.repo.GetOnCallSchedule(scheduleID, tenantID); err != nil {
+		if err.Error() == "on-call schedule not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "On-call schedule not found"})
+			return
+		}
+		h.logger.Error("Failed to get on-call schedule", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	if err := h.repo.DeleteOnCallOverride(c.Param("override_id"), scheduleID); err != nil {
+		if err.Error() == "on-call override not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "On-call override not found"})
+			return
+		}
+		h.logger.Error("Failed to delete on-call override", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete on-call override"})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// validateOnCallSchedule checks rotations and members, and that contact channels are
+// valid channels that page someone directly
+func (h *Handler) validateOnCallSchedule(tenantID string, schedule *db.OnCallSchedule) error {
+	if err := notifications.ValidateOnCallSchedule(schedule); err != nil {
+		return err
+	}
+
+	for _, member := range schedule.Members {
+		if err := h.validateNotificationChannels(tenantID, member.Channels); err != nil {
+			return fmt.Errorf("member %s: %w", member.User, err)
+		}
+
+		var ids []string
+		for _, channel := range member.Channels {
+			if channel.ID != "" {
+				ids = append(ids, channel.ID)
+			}
+		}
+		stored, err := h.repo.GetNotificationChannelsByIDs(tenantID, ids)
+		if err != nil {
+			return err
+		}
+		for _, channel := range stored {
+			if channel.Type == notifications.ChannelTypeOnCall {
+				return fmt.Errorf("member %s: contact channels cannot be on-call channels", member.User)
+			}
+		}
+	}
+	return nil
+}
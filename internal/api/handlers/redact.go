@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+)
+
+// Responses mask plaintext credentials in monitor and channel configs (see
+// secrets.Redact). Updates restore values sent back masked, so a config read
+// from the API can be saved again as it is. If a config cannot be copied, it
+// is returned empty rather than unmasked.
+
+// monitorCredentials are the monitor fields that may hold credentials
+type monitorCredentials struct {
+	Target           string                `json:"target"`
+	Config           db.MonitorConfig      `json:"config"`
+	NotificationConf db.NotificationConfig `json:"notification_config"`
+}
+
+func redactMonitor(monitor *db.Monitor) *db.Monitor {
+	if monitor == nil {
+		return nil
+	}
+	var masked monitorCredentials
+	secrets.Redact(monitorCredentials{monitor.Target, monitor.Config, monitor.NotificationConf}, &masked)
+
+	redacted := *monitor
+	redacted.Target, redacted.Config, redacted.NotificationConf = masked.Target, masked.Config, masked.NotificationConf
+	return &redacted
+}
+
+func redactMonitors(monitors []*db.Monitor) []*db.Monitor {
+	redacted := make([]*db.Monitor, len(monitors))
+	for i, monitor := range monitors {
+		redacted[i] = redactMonitor(monitor)
+	}
+	return redacted
+}
+
+// restoreMonitorRequest puts back the credentials of stored that req still has masked
+func restoreMonitorRequest(req *CreateMonitorRequest, stored *db.Monitor) error {
+	incoming := monitorCredentials{Target: req.Target, Config: req.Config}
+	if req.NotificationConf != nil {
+		incoming.NotificationConf = *req.NotificationConf
+	}
+	var restored monitorCredentials
+	if err := secrets.Restore(incoming, monitorCredentials{stored.Target, stored.Config, stored.NotificationConf}, &restored); err != nil {
+		return err
+	}
+	req.Target, req.Config = restored.Target, restored.Config
+	if req.NotificationConf != nil {
+		*req.NotificationConf = restored.NotificationConf
+	}
+	return nil
+}
+
+func redactChannel(channel db.NotificationChannel) db.NotificationChannel {
+	var config db.JSONB
+	secrets.Redact(channel.Config, &config)
+	channel.Config = config
+	return channel
+}
+
+func redactChannels(channels []db.NotificationChannel) []db.NotificationChannel {
+	if channels == nil {
+		return nil
+	}
+	redacted := make([]db.NotificationChannel, len(channels))
+	for i, channel := range channels {
+		redacted[i] = redactChannel(channel)
+	}
+	return redacted
+}
+
+func redactNotificationConfig(conf db.NotificationConfig) db.NotificationConfig {
+	conf.Channels = redactChannels(conf.Channels)
+	return conf
+}
+
+func redactGroup(group *db.MonitorGroup) *db.MonitorGroup {
+	if group == nil {
+		return nil
+	}
+	redacted := *group
+	redacted.NotificationConf = redactNotificationConfig(group.NotificationConf)
+	if group.Members != nil {
+		redacted.Members = make([]db.MonitorGroupMember, len(group.Members))
+		for i, member := range group.Members {
+			member.Monitor = redactMonitor(member.Monitor)
+			redacted.Members[i] = member
+		}
+	}
+	return &redacted
+}
+
+func redactGroups(groups []*db.MonitorGroup) []*db.MonitorGroup {
+	redacted := make([]*db.MonitorGroup, len(groups))
+	for i, group := range groups {
+		redacted[i] = redactGroup(group)
+	}
+	return redacted
+}
+
+func redactAlertRule(rule *db.MonitorGroupAlertRule) *db.MonitorGroupAlertRule {
+	redacted := *rule
+	redacted.NotificationChannels = redactChannels(rule.NotificationChannels)
+	return &redacted
+}
+
+func redactEscalationPolicy(policy *db.EscalationPolicy) *db.EscalationPolicy {
+	redacted := *policy
+	redacted.Steps = make([]db.EscalationStep, len(policy.Steps))
+	for i, step := range policy.Steps {
+		step.Channels = redactChannels(step.Channels)
+		redacted.Steps[i] = step
+	}
+	return &redacted
+}
+
+func redactEscalationPolicies(policies []*db.EscalationPolicy) []*db.EscalationPolicy {
+	redacted := make([]*db.EscalationPolicy, len(policies))
+	for i, policy := range policies {
+		redacted[i] = redactEscalationPolicy(policy)
+	}
+	return redacted
+}
+
+func redactOnCallSchedule(schedule *db.OnCallSchedule) *db.OnCallSchedule {
+	redacted := *schedule
+	redacted.Members = make(db.OnCallMembers, len(schedule.Members))
+	for i, member := range schedule.Members {
+		member.Channels = redactChannels(member.Channels)
+		redacted.Members[i] = member
+	}
+	return &redacted
+}
+
+func redactOnCallSchedules(schedules []*db.OnCallSchedule) []*db.OnCallSchedule {
+	redacted := make([]*db.OnCallSchedule, len(schedules))
+	for i, schedule := range schedules {
+		redacted[i] = redactOnCallSchedule(schedule)
+	}
+	return redacted
+}
+
+func redactStoredChannels(channels []*db.NotificationChannel) []*db.NotificationChannel {
+	redacted := make([]*db.NotificationChannel, len(channels))
+	for i, channel := range channels {
+		masked := redactChannel(*channel)
+		redacted[i] = &masked
+	}
+	return redacted
+}
+
+// redactTarget masks a password in a monitor's target URL
+func redactTarget(target string) string {
+	var masked string
+	secrets.Redact(target, &masked)
+	return masked
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+	"go.uber.org/zap"
+)
+
+type CreateSecretRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=255"`
+	Value       string `json:"value" binding:"required"`
+	Description string `json:"description"`
+}
+
+// UpdateSecretRequest changes the description and, when given, the value. A
+// secret cannot be renamed as its references would break.
+type UpdateSecretRequest struct {
+	Value       *string `json:"value"`
+	Description string  `json:"description"`
+}
+
+// ListSecrets returns the tenant's secrets without their values
+func (h *Handler) ListSecrets(c *gin.Context) {
+	tenantID := c.GetString("tenant_id")
+
+	list, err := h.repo.GetSecretsByTenant(tenantID)
+	if err != nil {
+		h.logger.Error("Failed to list secrets", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"secrets": list})
+}
+
+func (h *Handler) GetSecret(c *gin.Context) {
+	secretID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	secret, err := h.repo.GetSecret(secretID, tenantID)
+	if err != nil {
+		if err.Error() == "secret not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Secret not found"})
+			return
+		}
+		h.logger.Error("Failed to get secret", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, secret)
+}
+
+func (h *Handler) CreateSecret(c *gin.Context) {
+	if h.keyring == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Secrets are not configured"})
+		return
+	}
+
+	var req CreateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !secrets.ValidName(req.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name may only contain letters, digits, '_', '-' and '.'"})
+		return
+	}
+
+	now := time.Now()
+	secret := &db.Secret{
+		ID:          uuid.New().String(),
+		TenantID:    c.GetString("tenant_id"),
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedBy:   c.GetString("user_email"),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := h.keyring.SealSecret(secret, req.Value); err != nil {
+		h.logger.Error("Failed to encrypt secret", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create secret"})
+		return
+	}
+
+	if err := h.repo.CreateSecret(secret); err != nil {
+		if err.Error() == "secret already exists" {
+			c.JSON(http.StatusConflict, gin.H{"error": "A secret with this name already exists"})
+			return
+		}
+		h.logger.Error("Failed to create secret", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create secret"})
+		return
+	}
+
+	h.logger.Info("Secret created",
+		zap.String("secret_id", secret.ID),
+		zap.String("secret_name", secret.Name),
+		zap.String("tenant_id", secret.TenantID),
+	)
+
+	c.JSON(http.StatusCreated, secret)
+}
+
+// UpdateSecret rotates the value. Checks and notifications pick it up the next
+// time they run.
+func (h *Handler) UpdateSecret(c *gin.Context) {
+	secretID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	secret, err := h.repo.GetSecret(secretID, tenantID)
+	if err != nil {
+		if err.Error() == "secret not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Secret not found"})
+			return
+		}
+		h.logger.Error("Failed to get secret", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	var req UpdateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Value != nil {
+		if *req.Value == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "value cannot be empty"})
+			return
+		}
+		if h.keyring == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Secrets are not configured"})
+			return
+		}
+		if err := h.keyring.SealSecret(secret, *req.Value); err != nil {
+			h.logger.Error("Failed to encrypt secret", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update secret"})
+			return
+		}
+	}
+	secret.Description = req.Description
+	secret.UpdatedAt = time.Now()
+
+	if err := h.repo.UpdateSecret(secret); err != nil {
+		h.logger.Error("Failed to update secret", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update secret"})
+		return
+	}
+
+	c.JSON(http.StatusOK, secret)
+}
+
+func (h *Handler) DeleteSecret(c *gin.Context) {
+	secretID := c.Param("id")
+	tenantID := c.GetString("tenant_id")
+
+	secret, err := h.repo.GetSecret(secretID, tenantID)
+	if err != nil {
+		if err.Error() == "secret not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Secret not found"})
+			return
+		}
+		h.logger.Error("Failed to get secret", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	references, err := h.repo.CountSecretReferences(secret.Name, tenantID)
+	if err != nil {
+		h.logger.Error("Failed to count secret references", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if references > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":      "Secret is still in use",
+			"references": references,
+		})
+		return
+	}
+
+	if err := h.repo.DeleteSecret(secretID, tenantID); err != nil {
+		h.logger.Error("Failed to delete secret", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete secret"})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// validateSecretReferences checks that every secret referenced in config exists
+func (h *Handler) validateSecretReferences(tenantID string, config interface{}) error {
+	names, err := secrets.References(config)
+	if err != nil || len(names) == 0 {
+		return err
+	}
+
+	stored, err := h.repo.GetSecretsByNames(tenantID, names)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(stored))
+	for _, secret := range stored {
+		existing[secret.Name] = true
+	}
+	for _, name := range names {
+		if !existing[name] {
+			return fmt.Errorf("secret %q does not exist", name)
+		}
+	}
+	return nil
+}
@@ -32,26 +32,6 @@ func (h *Handler) BulkUpdateMonitors(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented yet"})
 }
 
-func (h *Handler) ListNotificationChannels(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented yet"})
-}
-
-func (h *Handler) CreateNotificationChannel(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented yet"})
-}
-
-func (h *Handler) UpdateNotificationChannel(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented yet"})
-}
-
-func (h *Handler) DeleteNotificationChannel(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented yet"})
-}
-
-func (h *Handler) TestNotification(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented yet"})
-}
-
 func (h *Handler) GetOverview(c *gin.Context) {
 	tenantID := c.GetString("tenant_id")
 
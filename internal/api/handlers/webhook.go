@@ -1,11 +1,72 @@
 package handlers
 
 import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/leozw/uptime-guardian/internal/db"
+	"go.uber.org/zap"
 )
 
-// Webhook handlers - placeholder for now
+// maxHeartbeatMessage caps the message a job can attach to a ping
+const maxHeartbeatMessage = 1024
+
+// WebhookHandler receives heartbeat pings. Jobs call /ping/{token} when they
+// succeed and /ping/{token}/fail when they fail, optionally with ?duration= (in
+// seconds) and a short message as the body or ?msg=. The worker evaluates the
+// ping on its next scheduling round.
 func (h *Handler) WebhookHandler(c *gin.Context) {
-	// TODO: Implement webhook handling
-	c.JSON(200, gin.H{"status": "ok"})
+	monitor, err := h.repo.GetMonitorByHeartbeatToken(c.Param("token"))
+	if err != nil {
+		if err.Error() == "monitor not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Heartbeat not found"})
+			return
+		}
+		h.logger.Error("Failed to get heartbeat monitor", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	ping := &db.HeartbeatPing{
+		ID:         uuid.New().String(),
+		MonitorID:  monitor.ID,
+		Status:     db.StatusUp,
+		Message:    c.Query("msg"),
+		SourceIP:   c.ClientIP(),
+		ReceivedAt: time.Now(),
+	}
+	if strings.HasSuffix(c.FullPath(), "/fail") {
+		ping.Status = db.StatusDown
+	}
+
+	if value := c.Query("duration"); value != "" {
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "duration must be a number of seconds"})
+			return
+		}
+		durationMs := int(seconds * 1000)
+		ping.DurationMs = &durationMs
+	}
+
+	if ping.Message == "" && c.Request.Body != nil {
+		body, _ := io.ReadAll(io.LimitReader(c.Request.Body, maxHeartbeatMessage))
+		ping.Message = strings.TrimSpace(strings.ToValidUTF8(string(body), ""))
+	}
+	if len(ping.Message) > maxHeartbeatMessage {
+		ping.Message = strings.ToValidUTF8(ping.Message[:maxHeartbeatMessage], "")
+	}
+
+	if err := h.repo.CreateHeartbeatPing(ping); err != nil {
+		h.logger.Error("Failed to record heartbeat ping", zap.Error(err), zap.String("monitor_id", monitor.ID))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record ping"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
@@ -12,6 +12,16 @@ func SetupRoutes(r *gin.Engine, h *handlers.Handler, kc *keycloak.Client) {
 	r.GET("/health", h.Health)
 	r.GET("/ready", h.Ready)
 
+	// Heartbeat pings, authenticated by the secret token in the URL
+	ping := r.Group("/ping/:token")
+	ping.Use(middleware.RateLimit())
+	{
+		ping.GET("", h.WebhookHandler)
+		ping.POST("", h.WebhookHandler)
+		ping.GET("/fail", h.WebhookHandler)
+		ping.POST("/fail", h.WebhookHandler)
+	}
+
 	// API v1
 	v1 := r.Group("/api/v1")
 	v1.Use(middleware.Auth(kc), middleware.Tenant())
@@ -51,6 +61,45 @@ func SetupRoutes(r *gin.Engine, h *handlers.Handler, kc *keycloak.Client) {
 		notifications.PUT("/channels/:id", h.UpdateNotificationChannel)
 		notifications.DELETE("/channels/:id", h.DeleteNotificationChannel)
 		notifications.POST("/test", h.TestNotification)
+		notifications.GET("/deliveries", h.ListNotificationDeliveries)
+		notifications.GET("/templates", h.ListNotificationTemplates)
+		notifications.POST("/templates", h.CreateNotificationTemplate)
+		notifications.PUT("/templates/:id", h.UpdateNotificationTemplate)
+		notifications.DELETE("/templates/:id", h.DeleteNotificationTemplate)
+		notifications.POST("/templates/preview", h.PreviewNotificationTemplate)
+	}
+
+	// Escalation policies
+	escalation := v1.Group("/escalation-policies")
+	{
+		escalation.GET("", h.ListEscalationPolicies)
+		escalation.POST("", h.CreateEscalationPolicy)
+		escalation.GET("/:id", h.GetEscalationPolicy)
+		escalation.PUT("/:id", h.UpdateEscalationPolicy)
+		escalation.DELETE("/:id", h.DeleteEscalationPolicy)
+	}
+
+	// Secrets
+	secrets := v1.Group("/secrets")
+	{
+		secrets.GET("", h.ListSecrets)
+		secrets.POST("", h.CreateSecret)
+		secrets.GET("/:id", h.GetSecret)
+		secrets.PUT("/:id", h.UpdateSecret)
+		secrets.DELETE("/:id", h.DeleteSecret)
+	}
+
+	// On-call schedules
+	oncall := v1.Group("/oncall-schedules")
+	{
+		oncall.GET("", h.ListOnCallSchedules)
+		oncall.POST("", h.CreateOnCallSchedule)
+		oncall.GET("/:id", h.GetOnCallSchedule)
+		oncall.PUT("/:id", h.UpdateOnCallSchedule)
+		oncall.DELETE("/:id", h.DeleteOnCallSchedule)
+		oncall.GET("/:id/on-call", h.GetOnCall)
+		oncall.POST("/:id/overrides", h.CreateOnCallOverride)
+		oncall.DELETE("/:id/overrides/:override_id", h.DeleteOnCallOverride)
 	}
 
 	// Monitor Groups
@@ -69,6 +118,7 @@ func SetupRoutes(r *gin.Engine, h *handlers.Handler, kc *keycloak.Client) {
 		// Group status and monitoring
 		groups.GET("/:id/status", h.GetMonitorGroupStatus)
 		groups.GET("/:id/incidents", h.GetMonitorGroupIncidents)
+		groups.POST("/:id/incidents/:incident_id/acknowledge", h.AcknowledgeGroupIncident)
 		groups.POST("/:id/slo", h.SetMonitorGroupSLO)
 
 		// Alert rules
@@ -0,0 +1,298 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// Which operators each assertion source accepts
+var assertionOperators = map[string][]string{
+	db.AssertJSON:         {db.OpEquals, db.OpNotEquals, db.OpContains, db.OpNotContains, db.OpMatches, db.OpNotMatches, db.OpExists, db.OpNotExists, db.OpLessThan, db.OpGreaterThan},
+	db.AssertBody:         {db.OpContains, db.OpNotContains, db.OpMatches, db.OpNotMatches},
+	db.AssertHeader:       {db.OpEquals, db.OpNotEquals, db.OpContains, db.OpNotContains, db.OpMatches, db.OpNotMatches, db.OpExists, db.OpNotExists},
+	db.AssertBodySize:     {db.OpLessThan, db.OpGreaterThan},
+	db.AssertResponseTime: {db.OpLessThan},
+	db.AssertFinalURL:     {db.OpEquals, db.OpNotEquals, db.OpContains, db.OpNotContains, db.OpMatches, db.OpNotMatches, db.OpIsHTTPS},
+}
+
+// assertionResponse is what assertions are evaluated against
+type assertionResponse struct {
+	header   http.Header
+	body     []byte
+	duration time.Duration
+	urls     []*url.URL // Every URL requested, the last is the final one
+
+	doc    interface{}
+	docErr error
+	parsed bool
+}
+
+func (r *assertionResponse) json() (interface{}, error) {
+	if !r.parsed {
+		r.docErr = json.Unmarshal(r.body, &r.doc)
+		r.parsed = true
+	}
+	return r.doc, r.docErr
+}
+
+// evaluateAssertions checks every assertion and records each outcome in
+// details["assertions"]. It returns the status the failed assertions call for
+// (up when all passed) and the first failure of that severity.
+func evaluateAssertions(assertions []db.HTTPAssertion, resp *assertionResponse, details db.JSONB) (db.CheckStatus, string) {
+	status, message := db.StatusUp, ""
+	var outcomes []map[string]interface{}
+
+	for _, assertion := range assertions {
+		actual, passed, err := evaluateAssertion(assertion, resp)
+
+		outcome := map[string]interface{}{
+			"source":   assertion.Source,
+			"operator": assertion.Operator,
+			"passed":   passed,
+		}
+		if assertion.Path != "" {
+			outcome["path"] = assertion.Path
+		}
+		if assertion.Value != "" {
+			outcome["expected"] = assertion.Value
+		}
+		if actual != "" {
+			outcome["actual"] = truncate(actual, 200)
+		}
+		if err != nil {
+			outcome["error"] = err.Error()
+		}
+		outcomes = append(outcomes, outcome)
+
+		if passed {
+			continue
+		}
+		failure := describeAssertion(assertion)
+		if err != nil {
+			failure += ": " + err.Error()
+		} else if actual != "" {
+			failure += fmt.Sprintf(" (got %q)", truncate(actual, 100))
+		}
+
+		if assertion.Severity == string(db.StatusDegraded) {
+			if status == db.StatusUp {
+				status, message = db.StatusDegraded, failure
+			}
+		} else if status != db.StatusDown {
+			status, message = db.StatusDown, failure
+		}
+	}
+
+	details["assertions"] = outcomes
+	return status, message
+}
+
+// evaluateAssertion returns the value the assertion looked at and whether it passed
+func evaluateAssertion(assertion db.HTTPAssertion, resp *assertionResponse) (string, bool, error) {
+	switch assertion.Source {
+	case db.AssertJSON:
+		doc, err := resp.json()
+		if err != nil {
+			return "", false, fmt.Errorf("response is not JSON")
+		}
+		nodes, err := evalJSONPath(doc, assertion.Path)
+		if err != nil {
+			return "", false, err
+		}
+		return compareValues(assertion, nodes)
+
+	case db.AssertBody:
+		// The body itself is too long to be worth reporting
+		_, passed, err := compareValues(assertion, []interface{}{string(resp.body)})
+		return "", passed, err
+
+	case db.AssertHeader:
+		var values []interface{}
+		for _, value := range resp.header.Values(assertion.Path) {
+			values = append(values, value)
+		}
+		return compareValues(assertion, values)
+
+	case db.AssertBodySize:
+		return compareValues(assertion, []interface{}{float64(len(resp.body))})
+
+	case db.AssertResponseTime:
+		return compareValues(assertion, []interface{}{float64(resp.duration.Milliseconds())})
+
+	case db.AssertFinalURL:
+		if len(resp.urls) == 0 {
+			return "", false, fmt.Errorf("no URL was requested")
+		}
+		finalURL := resp.urls[len(resp.urls)-1].String()
+		if assertion.Operator == db.OpIsHTTPS {
+			return finalURL, endsOnHTTPS(resp.urls), nil
+		}
+		return compareValues(assertion, []interface{}{finalURL})
+	}
+	return "", false, fmt.Errorf("unknown assertion source %q", assertion.Source)
+}
+
+// compareValues applies the assertion's operator to the selected values. Negated
+// operators pass when no value matches, the others when any value does.
+func compareValues(assertion db.HTTPAssertion, values []interface{}) (string, bool, error) {
+	actual := ""
+	if len(values) > 0 {
+		actual = jsonValueString(values[0])
+	}
+
+	switch assertion.Operator {
+	case db.OpExists:
+		return actual, len(values) > 0, nil
+	case db.OpNotExists:
+		return actual, len(values) == 0, nil
+	}
+	if len(values) == 0 {
+		return "", false, fmt.Errorf("%s not found", describeSubject(assertion))
+	}
+
+	operator, negated := assertion.Operator, false
+	switch operator {
+	case db.OpNotEquals:
+		operator, negated = db.OpEquals, true
+	case db.OpNotContains:
+		operator, negated = db.OpContains, true
+	case db.OpNotMatches:
+		operator, negated = db.OpMatches, true
+	}
+
+	var re *regexp.Regexp
+	var limit float64
+	var err error
+	switch operator {
+	case db.OpMatches:
+		if re, err = regexp.Compile(assertion.Value); err != nil {
+			return actual, false, fmt.Errorf("invalid regex: %v", err)
+		}
+	case db.OpLessThan, db.OpGreaterThan:
+		if limit, err = strconv.ParseFloat(assertion.Value, 64); err != nil {
+			return actual, false, fmt.Errorf("value must be a number")
+		}
+	}
+
+	matches := func(value interface{}) bool {
+		s := jsonValueString(value)
+		switch operator {
+		case db.OpEquals:
+			return s == assertion.Value
+		case db.OpContains:
+			// Arrays contain elements, everything else substrings
+			if list, ok := value.([]interface{}); ok {
+				for _, item := range list {
+					if jsonValueString(item) == assertion.Value {
+						return true
+					}
+				}
+				return false
+			}
+			return strings.Contains(s, assertion.Value)
+		case db.OpMatches:
+			return re.MatchString(s)
+		case db.OpLessThan, db.OpGreaterThan:
+			n, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return false
+			}
+			if operator == db.OpLessThan {
+				return n < limit
+			}
+			return n > limit
+		}
+		return false
+	}
+
+	for _, value := range values {
+		if matches(value) {
+			if s := jsonValueString(value); len(values) > 1 {
+				actual = s
+			}
+			return actual, !negated, nil
+		}
+	}
+	return actual, negated, nil
+}
+
+func describeSubject(assertion db.HTTPAssertion) string {
+	switch assertion.Source {
+	case db.AssertJSON:
+		return assertion.Path
+	case db.AssertHeader:
+		return "header " + assertion.Path
+	case db.AssertBodySize:
+		return "body size"
+	case db.AssertResponseTime:
+		return "response time"
+	case db.AssertFinalURL:
+		return "final URL"
+	}
+	return "body"
+}
+
+func describeAssertion(assertion db.HTTPAssertion) string {
+	description := fmt.Sprintf("%s %s", describeSubject(assertion), strings.ReplaceAll(assertion.Operator, "_", " "))
+	if assertion.Value != "" {
+		description += fmt.Sprintf(" %q", assertion.Value)
+	}
+	return "Assertion failed: " + description
+}
+
+// ValidateAssertions checks sources, operators, paths and values of a monitor's assertions
+func ValidateAssertions(assertions []db.HTTPAssertion) error {
+	for i, assertion := range assertions {
+		operators, ok := assertionOperators[assertion.Source]
+		if !ok {
+			return fmt.Errorf("assertion %d: source must be json, body, header, body_size, response_time or final_url", i+1)
+		}
+		valid := false
+		for _, operator := range operators {
+			if operator == assertion.Operator {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("assertion %d: operator must be one of %s for %s", i+1, strings.Join(operators, ", "), assertion.Source)
+		}
+
+		switch assertion.Source {
+		case db.AssertJSON:
+			if err := ValidateJSONPath(assertion.Path); err != nil {
+				return fmt.Errorf("assertion %d: %v", i+1, err)
+			}
+		case db.AssertHeader:
+			if assertion.Path == "" {
+				return fmt.Errorf("assertion %d: path must name the header", i+1)
+			}
+		}
+
+		switch assertion.Operator {
+		case db.OpMatches, db.OpNotMatches:
+			if _, err := regexp.Compile(assertion.Value); err != nil {
+				return fmt.Errorf("assertion %d: invalid regex: %v", i+1, err)
+			}
+		case db.OpLessThan, db.OpGreaterThan:
+			if _, err := strconv.ParseFloat(assertion.Value, 64); err != nil {
+				return fmt.Errorf("assertion %d: value must be a number", i+1)
+			}
+		}
+
+		switch assertion.Severity {
+		case "", string(db.StatusDown), string(db.StatusDegraded):
+		default:
+			return fmt.Errorf("assertion %d: severity must be down or degraded", i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type GRPCChecker struct{}
+
+func NewGRPCChecker() *GRPCChecker {
+	return &GRPCChecker{}
+}
+
+// Check calls grpc.health.v1.Health/Check on the host:port target
+func (g *GRPCChecker) Check(monitor *db.Monitor, region string) *db.CheckResult {
+	result := &db.CheckResult{
+		MonitorID: monitor.ID,
+		TenantID:  monitor.TenantID,
+		Region:    region,
+		Details:   make(db.JSONB),
+	}
+	result.Details["service"] = monitor.Config.GRPCService
+
+	host, _, err := net.SplitHostPort(monitor.Target)
+	if err != nil {
+		result.Status = db.StatusDown
+		result.Error = fmt.Sprintf("Invalid target: %v", err)
+		return result
+	}
+
+	creds := insecure.NewCredentials()
+	if monitor.Config.GRPCTLS {
+		tlsConfig, err := buildTLSConfig(monitor.Config.TLS, host)
+		if err != nil {
+			result.Status = db.StatusDown
+			result.Error = fmt.Sprintf("Invalid TLS config: %v", err)
+			return result
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(monitor.Target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		result.Status = db.StatusDown
+		result.Error = fmt.Sprintf("Failed to create client: %v", err)
+		return result
+	}
+	defer conn.Close()
+
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if len(monitor.Config.GRPCMetadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(monitor.Config.GRPCMetadata))
+	}
+
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{
+		Service: monitor.Config.GRPCService,
+	})
+	duration := time.Since(start)
+
+	result.ResponseTimeMs = int(duration.Milliseconds())
+
+	if err != nil {
+		st := status.Convert(err)
+		result.Details["grpc_code"] = st.Code().String()
+		result.Status = db.StatusDown
+		switch st.Code() {
+		case codes.Unimplemented:
+			result.Error = "Server does not implement the gRPC health checking protocol"
+		case codes.NotFound:
+			result.Error = fmt.Sprintf("Service %q is unknown to the server", monitor.Config.GRPCService)
+		default:
+			result.Error = fmt.Sprintf("Health check failed: %s", st.Message())
+		}
+		return result
+	}
+
+	result.Details["serving_status"] = resp.Status.String()
+
+	switch resp.Status {
+	case healthpb.HealthCheckResponse_SERVING:
+		result.Status = db.StatusUp
+	case healthpb.HealthCheckResponse_NOT_SERVING:
+		result.Status = db.StatusDown
+		result.Error = "Service is not serving"
+	case healthpb.HealthCheckResponse_SERVICE_UNKNOWN:
+		result.Status = db.StatusDown
+		result.Error = fmt.Sprintf("Service %q is unknown to the server", monitor.Config.GRPCService)
+	default:
+		result.Status = db.StatusDegraded
+		result.Error = fmt.Sprintf("Service reported status %s", resp.Status)
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+package checks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// defaultHeartbeatGrace is how late a ping may arrive when the monitor sets no grace period
+const defaultHeartbeatGrace = 60 * time.Second
+
+// HeartbeatChecker evaluates the pings jobs send to a heartbeat monitor's ping
+// URL instead of connecting anywhere itself
+type HeartbeatChecker struct {
+	repo *db.Repository
+}
+
+func NewHeartbeatChecker(repo *db.Repository) *HeartbeatChecker {
+	return &HeartbeatChecker{repo: repo}
+}
+
+func (hc *HeartbeatChecker) Check(monitor *db.Monitor, region string) *db.CheckResult {
+	result := &db.CheckResult{
+		MonitorID: monitor.ID,
+		TenantID:  monitor.TenantID,
+		Region:    region,
+		Details:   make(db.JSONB),
+	}
+
+	ping, err := hc.repo.GetLatestHeartbeatPing(monitor.ID)
+	if err != nil {
+		// Not the job's fault, so it is not reported as down
+		result.Status = db.StatusDegraded
+		result.Error = fmt.Sprintf("Failed to get last ping: %v", err)
+		return result
+	}
+
+	grace := time.Duration(monitor.Config.HeartbeatGracePeriod) * time.Second
+	if grace <= 0 {
+		grace = defaultHeartbeatGrace
+	}
+	expectedEvery := time.Duration(monitor.Interval)*time.Second + grace
+
+	// New monitors get a full period for their first ping
+	since := monitor.CreatedAt
+	if ping != nil {
+		since = ping.ReceivedAt
+		result.Details["last_ping_at"] = ping.ReceivedAt.Format(time.RFC3339)
+		result.Details["source_ip"] = ping.SourceIP
+		if ping.DurationMs != nil {
+			result.ResponseTimeMs = *ping.DurationMs
+			result.Details["duration_ms"] = *ping.DurationMs
+		}
+		if ping.Message != "" {
+			result.Details["message"] = ping.Message
+		}
+	}
+
+	if late := time.Since(since); late > expectedEvery {
+		result.Status = db.StatusDown
+		if ping == nil {
+			result.Error = fmt.Sprintf("No ping received since the monitor was created %s ago", late.Round(time.Second))
+		} else {
+			result.Error = fmt.Sprintf("No ping received in %s, expected every %s", late.Round(time.Second), expectedEvery)
+		}
+		return result
+	}
+
+	if ping != nil && ping.Status == db.StatusDown {
+		result.Status = db.StatusDown
+		result.Error = "Job reported a failure"
+		if ping.Message != "" {
+			result.Error = fmt.Sprintf("Job reported a failure: %s", ping.Message)
+		}
+		return result
+	}
+
+	result.Status = db.StatusUp
+	return result
+}
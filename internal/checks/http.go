@@ -11,19 +11,29 @@ import (
     "github.com/leozw/uptime-guardian/internal/db"
 )
 
+// maxHTTPBody caps how much of a response is read. Larger responses are cut
+// short, so they are not downloaded in full on every check.
+const maxHTTPBody = 10 << 20
+
 type HTTPChecker struct {
-    client *http.Client
+    client     *http.Client
+    transports *transportCache
+    tokens     *tokenCache
 }
 
 func NewHTTPChecker() *HTTPChecker {
+    transport := &http.Transport{
+        TLSClientConfig: &tls.Config{
+            InsecureSkipVerify: false,
+        },
+        // Every check opens a new connection so DNS, connect and TLS are timed
+        DisableKeepAlives: true,
+    }
+    
     return &HTTPChecker{
         client: &http.Client{
             Timeout: 30 * time.Second,
-            Transport: &http.Transport{
-                TLSClientConfig: &tls.Config{
-                    InsecureSkipVerify: false,
-                },
-            },
+            Transport: transport,
             CheckRedirect: func(req *http.Request, via []*http.Request) error {
                 if len(via) >= 10 {
                     return fmt.Errorf("stopped after 10 redirects")
@@ -31,6 +41,8 @@ func NewHTTPChecker() *HTTPChecker {
                 return nil
             },
         },
+        transports: newTransportCache(transport),
+        tokens:     newTokenCache(),
     }
 }
 
@@ -59,8 +71,12 @@ func (h *HTTPChecker) Check(monitor *db.Monitor, region string) *db.CheckResult
         return result
     }
     
-    // Set headers
+    // Set headers, Host overrides the request's host
     for k, v := range monitor.Config.Headers {
+        if strings.EqualFold(k, "Host") {
+            req.Host = v
+            continue
+        }
         req.Header.Set(k, v)
     }
     
@@ -69,16 +85,52 @@ func (h *HTTPChecker) Check(monitor *db.Monitor, region string) *db.CheckResult
         req.SetBasicAuth(monitor.Config.BasicAuth.Username, monitor.Config.BasicAuth.Password)
     }
     
-    // Set custom timeout
+    // Get transport for the monitor's TLS options and proxy
+    transport, err := h.transports.get(monitor.Config.TLS, monitor.Config.ProxyURL)
+    if err != nil {
+        result.Status = db.StatusDown
+        result.Error = fmt.Sprintf("Failed to create transport: %v", err)
+        return result
+    }
+    
+    // Set custom timeout and redirect policy
     client := h.client
-    if monitor.Timeout > 0 {
+    followRedirects := monitor.Config.FollowRedirects == nil || *monitor.Config.FollowRedirects
+    if monitor.Timeout > 0 || !followRedirects || transport != h.client.Transport {
         client = &http.Client{
-            Timeout: time.Duration(monitor.Timeout) * time.Second,
-            Transport: h.client.Transport,
+            Timeout: h.client.Timeout,
+            Transport: transport,
             CheckRedirect: h.client.CheckRedirect,
         }
+        if monitor.Timeout > 0 {
+            client.Timeout = time.Duration(monitor.Timeout) * time.Second
+        }
+        if !followRedirects {
+            client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+                return http.ErrUseLastResponse
+            }
+        }
+    }
+    
+    result.Details = make(db.JSONB)
+    
+    // OAuth2 bearer token, a failure to get one is not the target's fault
+    if monitor.Config.OAuth2 != nil {
+        token, err := h.tokens.token(monitor.Config.OAuth2, transport)
+        if err != nil {
+            result.Status = db.StatusDegraded
+            result.Error = fmt.Sprintf("OAuth2 token request failed: %v", err)
+            result.Details["auth_error"] = err.Error()
+            result.Details[db.ConfigErrorDetail] = true
+            return result
+        }
+        token.SetAuthHeader(req)
     }
     
+    // Trace request phases
+    timing := &httpTiming{}
+    req = req.WithContext(timing.withTrace(req.Context()))
+    
     // Execute request
     start := time.Now()
     resp, err := client.Do(req)
@@ -88,6 +140,7 @@ func (h *HTTPChecker) Check(monitor *db.Monitor, region string) *db.CheckResult
         result.Status = db.StatusDown
         result.Error = fmt.Sprintf("Request failed: %v", err)
         result.ResponseTimeMs = int(duration.Milliseconds())
+        result.Details["timing"] = timing.phases(time.Now())
         return result
     }
     defer resp.Body.Close()
@@ -95,6 +148,32 @@ func (h *HTTPChecker) Check(monitor *db.Monitor, region string) *db.CheckResult
     result.StatusCode = resp.StatusCode
     result.ResponseTimeMs = int(duration.Milliseconds())
     
+    // A rejected token may have been revoked, get a new one next time
+    if monitor.Config.OAuth2 != nil && resp.StatusCode == http.StatusUnauthorized {
+        h.tokens.forget(monitor.Config.OAuth2, transport)
+    }
+    
+    // Read body, which also times the transfer
+    var bodyBytes []byte
+    var readErr error
+    needBody := monitor.Config.SearchString != "" || len(monitor.Config.Assertions) > 0
+    if needBody {
+        bodyBytes, readErr = io.ReadAll(io.LimitReader(resp.Body, maxHTTPBody+1))
+        if len(bodyBytes) > maxHTTPBody {
+            bodyBytes = bodyBytes[:maxHTTPBody]
+            result.Details["body_truncated"] = true
+        }
+    } else {
+        _, readErr = io.Copy(io.Discard, io.LimitReader(resp.Body, maxHTTPBody))
+    }
+    result.Details["timing"] = timing.phases(time.Now())
+    
+    // Record redirects
+    if chain := redirectChain(resp); len(chain) > 1 || resp.Header.Get("Location") != "" {
+        result.Details["redirect_chain"] = chain
+        result.Details["final_url"] = resp.Request.URL.String()
+    }
+    
     // Check expected status codes
     expectedCodes := monitor.Config.ExpectedStatusCodes
     if len(expectedCodes) == 0 {
@@ -115,15 +194,14 @@ func (h *HTTPChecker) Check(monitor *db.Monitor, region string) *db.CheckResult
         return result
     }
     
+    if needBody && readErr != nil {
+        result.Status = db.StatusDegraded
+        result.Error = fmt.Sprintf("Failed to read response body: %v", readErr)
+        return result
+    }
+    
     // Check body content if required
     if monitor.Config.SearchString != "" {
-        bodyBytes, err := io.ReadAll(resp.Body)
-        if err != nil {
-            result.Status = db.StatusDegraded
-            result.Error = fmt.Sprintf("Failed to read response body: %v", err)
-            return result
-        }
-        
         if !strings.Contains(string(bodyBytes), monitor.Config.SearchString) {
             result.Status = db.StatusDown
             result.Error = fmt.Sprintf("Search string not found in response")
@@ -132,5 +210,12 @@ func (h *HTTPChecker) Check(monitor *db.Monitor, region string) *db.CheckResult
     }
     
     result.Status = db.StatusUp
+    
+    // Check assertions
+    if len(monitor.Config.Assertions) > 0 {
+        response := &assertionResponse{header: resp.Header, body: bodyBytes, duration: duration, urls: redirectURLs(resp)}
+        result.Status, result.Error = evaluateAssertions(monitor.Config.Assertions, response, result.Details)
+    }
+    
     return result
 }
\ No newline at end of file
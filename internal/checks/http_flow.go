@@ -0,0 +1,294 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// maxFlowBody caps how much of each step's response is read for assertions and extraction
+const maxFlowBody = 1 << 20
+
+var flowVariable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// HTTPFlowChecker runs the steps of an http_flow monitor in order, like a user
+// journey: cookies and extracted variables carry over from one step to the next
+// and the flow stops at the first failing step
+type HTTPFlowChecker struct {
+	transports *transportCache
+}
+
+func NewHTTPFlowChecker() *HTTPFlowChecker {
+	return &HTTPFlowChecker{
+		transports: newTransportCache(http.DefaultTransport.(*http.Transport).Clone()),
+	}
+}
+
+func (f *HTTPFlowChecker) Check(monitor *db.Monitor, region string) *db.CheckResult {
+	result := &db.CheckResult{
+		MonitorID: monitor.ID,
+		TenantID:  monitor.TenantID,
+		Region:    region,
+		Details:   make(db.JSONB),
+	}
+
+	base, err := url.Parse(monitor.Target)
+	if err != nil {
+		result.Status = db.StatusDown
+		result.Error = fmt.Sprintf("Invalid URL: %v", err)
+		return result
+	}
+
+	transport, err := f.transports.get(monitor.Config.TLS, monitor.Config.ProxyURL)
+	if err != nil {
+		result.Status = db.StatusDown
+		result.Error = fmt.Sprintf("Failed to create transport: %v", err)
+		return result
+	}
+
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// A fresh jar per run so sessions never leak between checks
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Transport: transport, Jar: jar}
+
+	vars := make(map[string]string)
+	var steps []map[string]interface{}
+	var slow []string
+	var warnings []string
+
+	start := time.Now()
+	result.Status = db.StatusUp
+	for i, step := range monitor.Config.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+
+		report, err := f.runStep(ctx, client, base, step, vars)
+		report["name"] = name
+		steps = append(steps, report)
+		if code, ok := report["status_code"].(int); ok {
+			result.StatusCode = code
+		}
+
+		if err != nil {
+			report["error"] = err.Error()
+			result.Status = db.StatusDown
+			result.Error = fmt.Sprintf("Step %s failed: %v", name, err)
+			result.Details["failed_step"] = name
+			result.Details["failed_step_index"] = i
+			break
+		}
+		if report["slow"] == true {
+			slow = append(slow, name)
+		}
+		if warning, ok := report["warning"].(string); ok {
+			warnings = append(warnings, fmt.Sprintf("Step %s: %s", name, warning))
+		}
+	}
+	result.ResponseTimeMs = int(time.Since(start).Milliseconds())
+	result.Details["steps"] = steps
+
+	if result.Status == db.StatusUp && len(slow) > 0 {
+		result.Status = db.StatusDegraded
+		warnings = append(warnings, fmt.Sprintf("Slow steps: %s", strings.Join(slow, ", ")))
+		result.Details["slow_steps"] = slow
+	}
+	if result.Status != db.StatusDown && len(warnings) > 0 {
+		result.Status = db.StatusDegraded
+		result.Error = strings.Join(warnings, "; ")
+	}
+	return result
+}
+
+// runStep sends one step's request, checks its assertions and extracts its
+// variables. The report is stored in the result's details even when the step fails.
+func (f *HTTPFlowChecker) runStep(ctx context.Context, client *http.Client, base *url.URL, step db.HTTPFlowStep, vars map[string]string) (map[string]interface{}, error) {
+	report := make(map[string]interface{})
+
+	method := step.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	report["method"] = method
+
+	rawURL, err := substituteVariables(step.URL, vars)
+	if err != nil {
+		return report, err
+	}
+	target, err := base.Parse(rawURL)
+	if err != nil {
+		return report, fmt.Errorf("invalid URL: %v", err)
+	}
+	report["url"] = target.String()
+
+	body, err := substituteVariables(step.Body, vars)
+	if err != nil {
+		return report, err
+	}
+	var reqBody io.Reader
+	if body != "" {
+		reqBody = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), reqBody)
+	if err != nil {
+		return report, fmt.Errorf("failed to create request: %v", err)
+	}
+	for k, v := range step.Headers {
+		value, err := substituteVariables(v, vars)
+		if err != nil {
+			return report, err
+		}
+		if strings.EqualFold(k, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(k, value)
+	}
+
+	timing := &httpTiming{}
+	req = req.WithContext(timing.withTrace(ctx))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		report["response_time_ms"] = int(time.Since(start).Milliseconds())
+		report["timing"] = timing.phases(time.Now())
+		return report, fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxFlowBody))
+	duration := time.Since(start)
+	report["timing"] = timing.phases(time.Now())
+	report["status_code"] = resp.StatusCode
+	report["response_time_ms"] = int(duration.Milliseconds())
+	if chain := redirectChain(resp); len(chain) > 1 {
+		report["redirect_chain"] = chain
+	}
+	if err != nil {
+		return report, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if !flowStatusOK(step.ExpectedStatusCodes, resp.StatusCode) {
+		return report, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if step.SearchString != "" && !strings.Contains(string(respBody), step.SearchString) {
+		return report, fmt.Errorf("search string not found in response")
+	}
+	if step.MaxResponseTimeMs > 0 && duration > time.Duration(step.MaxResponseTimeMs)*time.Millisecond {
+		report["slow"] = true
+	}
+
+	if len(step.Assertions) > 0 {
+		response := &assertionResponse{header: resp.Header, body: respBody, duration: duration, urls: redirectURLs(resp)}
+		status, message := evaluateAssertions(step.Assertions, response, report)
+		switch status {
+		case db.StatusDown:
+			return report, fmt.Errorf("%s", message)
+		case db.StatusDegraded:
+			report["warning"] = message
+		}
+	}
+
+	if err := extractVariables(step.Extract, resp, respBody, vars); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func flowStatusOK(expected []int, code int) bool {
+	if len(expected) == 0 {
+		return code >= 200 && code < 300
+	}
+	for _, c := range expected {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// extractVariables stores the values a step extracts from its response in vars
+func extractVariables(extracts []db.HTTPFlowExtract, resp *http.Response, body []byte, vars map[string]string) error {
+	var doc interface{}
+	parsed := false
+
+	for _, extract := range extracts {
+		var value string
+		found := false
+
+		switch extract.From {
+		case db.ExtractFromJSON:
+			if !parsed {
+				if err := json.Unmarshal(body, &doc); err != nil {
+					return fmt.Errorf("extract %s: response is not JSON: %v", extract.Variable, err)
+				}
+				parsed = true
+			}
+			values, err := evalJSONPath(doc, extract.Path)
+			if err != nil {
+				return fmt.Errorf("extract %s: %v", extract.Variable, err)
+			}
+			if len(values) > 0 {
+				value, found = jsonValueString(values[0]), true
+			}
+		case db.ExtractFromHeader:
+			if values := resp.Header.Values(extract.Path); len(values) > 0 {
+				value, found = values[0], true
+			}
+		case db.ExtractFromRegex:
+			re, err := regexp.Compile(extract.Path)
+			if err != nil {
+				return fmt.Errorf("extract %s: invalid regex: %v", extract.Variable, err)
+			}
+			if match := re.FindSubmatch(body); match != nil {
+				value, found = string(match[0]), true
+				if len(match) > 1 {
+					value = string(match[1])
+				}
+			}
+		default:
+			return fmt.Errorf("extract %s: unknown source %q", extract.Variable, extract.From)
+		}
+
+		if !found {
+			return fmt.Errorf("extract %s: %s %q matched nothing", extract.Variable, extract.From, extract.Path)
+		}
+		vars[extract.Variable] = value
+	}
+	return nil
+}
+
+// substituteVariables replaces {{name}} with the variable's value
+func substituteVariables(s string, vars map[string]string) (string, error) {
+	var missing string
+	out := flowVariable.ReplaceAllStringFunc(s, func(match string) string {
+		name := flowVariable.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", fmt.Errorf("undefined variable %q", missing)
+	}
+	return out, nil
+}
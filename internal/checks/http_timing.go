@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// httpTiming records when each phase of a request starts and ends through
+// httptrace. On redirects every hop overwrites the previous one, so the phases
+// describe the final request.
+type httpTiming struct {
+	// Dials report from their own goroutines
+	mu sync.Mutex
+
+	dnsStart, dnsDone         time.Time
+	connectStart, connectDone time.Time
+	tlsStart, tlsDone         time.Time
+	gotConn, firstByte        time.Time
+	reused                    bool
+}
+
+// withTrace returns ctx with a client trace that fills in t
+func (t *httpTiming) withTrace(ctx context.Context) context.Context {
+	now := func(field *time.Time) {
+		t.mu.Lock()
+		*field = time.Now()
+		t.mu.Unlock()
+	}
+
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { now(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { now(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// Dialing several addresses (happy eyeballs) counts from the first attempt
+			if t.connectStart.IsZero() || !t.connectDone.IsZero() {
+				t.connectStart, t.connectDone = time.Now(), time.Time{}
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				now(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { now(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { now(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.gotConn, t.reused = time.Now(), info.Reused
+		},
+		GotFirstResponseByte: func() { now(&t.firstByte) },
+	})
+}
+
+// phases returns the duration of each phase that happened in milliseconds, with
+// done being when the body was read. TTFB runs from having a connection to the
+// first response byte, so it is the backend's time plus one round trip.
+func (t *httpTiming) phases(done time.Time) map[string]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	phases := make(map[string]float64)
+	add := func(phase string, start, end time.Time) {
+		if !start.IsZero() && !end.IsZero() && !end.Before(start) {
+			phases[phase] = durationMs(end.Sub(start))
+		}
+	}
+
+	if !t.reused {
+		add("dns", t.dnsStart, t.dnsDone)
+		add("connect", t.connectStart, t.connectDone)
+		add("tls", t.tlsStart, t.tlsDone)
+	}
+	add("ttfb", t.gotConn, t.firstByte)
+	add("transfer", t.firstByte, done)
+	return phases
+}
@@ -0,0 +1,125 @@
+package checks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegment is one step of a parsed JSONPath: a field name, an array
+// index (negative counts from the end) or a wildcard over all children
+type jsonPathSegment struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses the subset of JSONPath monitors need: $, .field,
+// ['field'], [index] and the [*] / .* wildcards
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", path)
+	}
+
+	var segments []jsonPathSegment
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("JSONPath %q has an empty field name", path)
+			}
+			if name == "*" {
+				segments = append(segments, jsonPathSegment{wildcard: true})
+			} else {
+				segments = append(segments, jsonPathSegment{field: name})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %q has an unclosed [", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			switch {
+			case inner == "*":
+				segments = append(segments, jsonPathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, jsonPathSegment{field: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("JSONPath %q has an invalid index %q", path, inner)
+				}
+				segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSONPath %q is invalid at %q", path, rest)
+		}
+	}
+	return segments, nil
+}
+
+// evalJSONPath returns the values path selects in doc, none when it does not match
+func evalJSONPath(doc interface{}, path string) ([]interface{}, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []interface{}{doc}
+	for _, segment := range segments {
+		var next []interface{}
+		for _, node := range nodes {
+			switch value := node.(type) {
+			case map[string]interface{}:
+				if segment.wildcard {
+					for _, child := range value {
+						next = append(next, child)
+					}
+				} else if child, ok := value[segment.field]; ok && !segment.isIndex {
+					next = append(next, child)
+				}
+			case []interface{}:
+				if segment.wildcard {
+					next = append(next, value...)
+				} else if segment.isIndex {
+					index := segment.index
+					if index < 0 {
+						index += len(value)
+					}
+					if index >= 0 && index < len(value) {
+						next = append(next, value[index])
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// jsonValueString renders a JSON value for comparisons and variables: strings
+// as they are, everything else as JSON
+func jsonValueString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(value)
+	return string(b)
+}
+
+// ValidateJSONPath checks that path is in the supported JSONPath subset
+func ValidateJSONPath(path string) error {
+	_, err := parseJSONPath(path)
+	return err
+}
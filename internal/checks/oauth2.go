@@ -0,0 +1,92 @@
+package checks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// tokenTimeout bounds each request to a token endpoint
+const tokenTimeout = 15 * time.Second
+
+// tokenCache keeps one token source per OAuth2 client and transport, so an
+// access token is reused until it expires and only then requested again
+type tokenCache struct {
+	mu      sync.Mutex
+	sources map[[sha256.Size]byte]oauth2.TokenSource
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{sources: make(map[[sha256.Size]byte]oauth2.TokenSource)}
+}
+
+func tokenKey(config *db.OAuth2Config, transport http.RoundTripper) [sha256.Size]byte {
+	raw, _ := json.Marshal(config)
+	return sha256.Sum256(append(raw, fmt.Sprintf("%p", transport)...))
+}
+
+// token returns a valid access token for config. The token endpoint is called
+// through transport, so it honors the monitor's TLS options and proxy.
+func (c *tokenCache) token(config *db.OAuth2Config, transport http.RoundTripper) (*oauth2.Token, error) {
+	key := tokenKey(config, transport)
+
+	c.mu.Lock()
+	source, ok := c.sources[key]
+	if !ok {
+		if len(c.sources) >= maxCachedTransports {
+			c.sources = make(map[[sha256.Size]byte]oauth2.TokenSource)
+		}
+		credentials := &clientcredentials.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			TokenURL:     config.TokenURL,
+			Scopes:       config.Scopes,
+		}
+		// The source keeps this context to refresh the token later
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{
+			Transport: transport,
+			Timeout:   tokenTimeout,
+		})
+		source = credentials.TokenSource(ctx)
+		c.sources[key] = source
+	}
+	c.mu.Unlock()
+
+	// Outside the lock so a slow token endpoint only holds up its own monitors
+	return source.Token()
+}
+
+// forget drops the cached token, e.g. after the target rejected it
+func (c *tokenCache) forget(config *db.OAuth2Config, transport http.RoundTripper) {
+	c.mu.Lock()
+	delete(c.sources, tokenKey(config, transport))
+	c.mu.Unlock()
+}
+
+// ValidateOAuth2Config checks that a monitor's OAuth2 client credentials are complete
+func ValidateOAuth2Config(config *db.OAuth2Config) error {
+	if config == nil {
+		return nil
+	}
+	// A token_url with secret references is only known once the worker resolves it
+	if !secrets.HasReferences(config.TokenURL) {
+		tokenURL, err := url.Parse(config.TokenURL)
+		if err != nil || (tokenURL.Scheme != "http" && tokenURL.Scheme != "https") || tokenURL.Host == "" {
+			return fmt.Errorf("token_url must be an http or https URL")
+		}
+	}
+	if config.ClientID == "" {
+		return fmt.Errorf("client_id is required")
+	}
+	return nil
+}
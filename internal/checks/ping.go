@@ -0,0 +1,223 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const (
+	defaultPingCount = 5
+	maxPingCount     = 20
+
+	// pingInterval spaces echo requests, pingReplyTimeout caps the wait for each reply
+	pingInterval     = 200 * time.Millisecond
+	pingReplyTimeout = 2 * time.Second
+)
+
+type PingChecker struct{}
+
+func NewPingChecker() *PingChecker {
+	return &PingChecker{}
+}
+
+// Check sends ping_count echo requests to the target and reports round-trip
+// times, jitter and packet loss
+func (p *PingChecker) Check(monitor *db.Monitor, region string) *db.CheckResult {
+	result := &db.CheckResult{
+		MonitorID: monitor.ID,
+		TenantID:  monitor.TenantID,
+		Region:    region,
+		Details:   make(db.JSONB),
+	}
+
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, monitor.Target)
+	if err != nil || len(addrs) == 0 {
+		result.Status = db.StatusDown
+		result.Error = fmt.Sprintf("Failed to resolve host: %v", err)
+		return result
+	}
+	ip := addrs[0].IP
+	result.Details["ip"] = ip.String()
+
+	conn, privileged, err := listenICMP(ip)
+	if err != nil {
+		result.Status = db.StatusDown
+		result.Error = fmt.Sprintf("Failed to open ICMP socket: %v", err)
+		return result
+	}
+	defer conn.Close()
+
+	count := monitor.Config.PingCount
+	if count <= 0 {
+		count = defaultPingCount
+	}
+	if count > maxPingCount {
+		count = maxPingCount
+	}
+
+	// Unprivileged sockets get their ID assigned by the kernel, which also
+	// filters replies to the socket; raw sockets see every reply on the host
+	id := rand.Intn(0xffff)
+	var dst net.Addr = &net.IPAddr{IP: ip}
+	if !privileged {
+		dst = &net.UDPAddr{IP: ip}
+	}
+
+	var rtts []time.Duration
+	sent := 0
+	for seq := 0; seq < count && time.Now().Before(deadline); seq++ {
+		if seq > 0 {
+			time.Sleep(pingInterval)
+		}
+		sent++
+		rtt, err := pingOnce(conn, ip, dst, id, seq, privileged, deadline)
+		if err != nil {
+			continue
+		}
+		rtts = append(rtts, rtt)
+	}
+
+	loss := 100 * float64(sent-len(rtts)) / float64(sent)
+	result.Details["packets_sent"] = sent
+	result.Details["packets_received"] = len(rtts)
+	result.Details["packet_loss"] = round2(loss)
+
+	if len(rtts) == 0 {
+		result.Status = db.StatusDown
+		result.Error = "100% packet loss"
+		return result
+	}
+
+	minRTT, maxRTT, total := rtts[0], rtts[0], time.Duration(0)
+	var jitter time.Duration
+	for i, rtt := range rtts {
+		if rtt < minRTT {
+			minRTT = rtt
+		}
+		if rtt > maxRTT {
+			maxRTT = rtt
+		}
+		total += rtt
+		if i > 0 {
+			diff := rtt - rtts[i-1]
+			if diff < 0 {
+				diff = -diff
+			}
+			jitter += diff
+		}
+	}
+	avg := total / time.Duration(len(rtts))
+	if len(rtts) > 1 {
+		// Mean deviation between consecutive replies
+		jitter /= time.Duration(len(rtts) - 1)
+	}
+
+	result.ResponseTimeMs = int(avg.Milliseconds())
+	result.Details["rtt_min_ms"] = durationMs(minRTT)
+	result.Details["rtt_avg_ms"] = durationMs(avg)
+	result.Details["rtt_max_ms"] = durationMs(maxRTT)
+	result.Details["jitter_ms"] = durationMs(jitter)
+
+	if threshold := monitor.Config.PingMaxPacketLoss; threshold > 0 && loss > threshold {
+		result.Status = db.StatusDegraded
+		result.Error = fmt.Sprintf("Packet loss %.1f%% above %.1f%%", loss, threshold)
+		return result
+	}
+	if threshold := monitor.Config.PingMaxLatencyMs; threshold > 0 && avg > time.Duration(threshold)*time.Millisecond {
+		result.Status = db.StatusDegraded
+		result.Error = fmt.Sprintf("Average round-trip time %.1fms above %dms", durationMs(avg), threshold)
+		return result
+	}
+
+	result.Status = db.StatusUp
+	return result
+}
+
+// listenICMP opens an unprivileged ICMP socket, falling back to a raw socket
+// when the kernel does not allow them (net.ipv4.ping_group_range)
+func listenICMP(ip net.IP) (*icmp.PacketConn, bool, error) {
+	network, raw, address := "udp4", "ip4:icmp", "0.0.0.0"
+	if ip.To4() == nil {
+		network, raw, address = "udp6", "ip6:ipv6-icmp", "::"
+	}
+	if conn, err := icmp.ListenPacket(network, address); err == nil {
+		return conn, false, nil
+	}
+	conn, err := icmp.ListenPacket(raw, address)
+	if err != nil {
+		return nil, false, err
+	}
+	return conn, true, nil
+}
+
+// pingOnce sends one echo request and waits for its reply
+func pingOnce(conn *icmp.PacketConn, ip net.IP, dst net.Addr, id, seq int, privileged bool, deadline time.Time) (time.Duration, error) {
+	var requestType, replyType icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	protocol := 1
+	if ip.To4() == nil {
+		requestType, replyType = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+		protocol = 58
+	}
+
+	request := icmp.Message{
+		Type: requestType,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("uptime-guardian")},
+	}
+	packet, err := request.Marshal(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if _, err := conn.WriteTo(packet, dst); err != nil {
+		return 0, err
+	}
+
+	wait := start.Add(pingReplyTimeout)
+	if deadline.Before(wait) {
+		wait = deadline
+	}
+	conn.SetReadDeadline(wait)
+
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return 0, err
+		}
+		reply, err := icmp.ParseMessage(protocol, buf[:n])
+		if err != nil || reply.Type != replyType {
+			continue
+		}
+		echo, ok := reply.Body.(*icmp.Echo)
+		if !ok || echo.Seq != seq || (privileged && echo.ID != id) {
+			continue
+		}
+		return time.Since(start), nil
+	}
+}
+
+func durationMs(d time.Duration) float64 {
+	return round2(float64(d) / float64(time.Millisecond))
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,46 @@
+package checks
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// redirectChain walks back from the final response to the first request and
+// returns every hop in order, with the Location of redirect responses
+func redirectChain(resp *http.Response) []map[string]interface{} {
+	var chain []map[string]interface{}
+	for r := resp; r != nil; r = r.Request.Response {
+		hop := map[string]interface{}{
+			"url":         r.Request.URL.String(),
+			"status_code": r.StatusCode,
+		}
+		if location := r.Header.Get("Location"); location != "" {
+			hop["location"] = location
+		}
+		chain = append([]map[string]interface{}{hop}, chain...)
+	}
+	return chain
+}
+
+// redirectURLs returns the URL of every request made to get resp, first to last
+func redirectURLs(resp *http.Response) []*url.URL {
+	var urls []*url.URL
+	for r := resp; r != nil; r = r.Request.Response {
+		urls = append([]*url.URL{r.Request.URL}, urls...)
+	}
+	return urls
+}
+
+// endsOnHTTPS reports whether a request ended on HTTPS without any redirect
+// going back to plain HTTP once it got there
+func endsOnHTTPS(urls []*url.URL) bool {
+	secure := false
+	for _, u := range urls {
+		if u.Scheme == "https" {
+			secure = true
+		} else if secure {
+			return false
+		}
+	}
+	return secure
+}
@@ -0,0 +1,153 @@
+package checks
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// maxTCPResponse caps how much of the server's response is read and matched
+const maxTCPResponse = 4096
+
+type TCPChecker struct{}
+
+func NewTCPChecker() *TCPChecker {
+	return &TCPChecker{}
+}
+
+// Check connects to the host:port target, optionally over TLS, sends the
+// configured payload and matches the response against the expectations
+func (t *TCPChecker) Check(monitor *db.Monitor, region string) *db.CheckResult {
+	result := &db.CheckResult{
+		MonitorID: monitor.ID,
+		TenantID:  monitor.TenantID,
+		Region:    region,
+		Details:   make(db.JSONB),
+	}
+
+	host, _, err := net.SplitHostPort(monitor.Target)
+	if err != nil {
+		result.Status = db.StatusDown
+		result.Error = fmt.Sprintf("Invalid target: %v", err)
+		return result
+	}
+
+	var expect *regexp.Regexp
+	if monitor.Config.TCPExpectRegex != "" {
+		expect, err = regexp.Compile(monitor.Config.TCPExpectRegex)
+		if err != nil {
+			result.Status = db.StatusDown
+			result.Error = fmt.Sprintf("Invalid expect regex: %v", err)
+			return result
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if monitor.Config.TCPTLS {
+		tlsConfig, err = buildTLSConfig(monitor.Config.TLS, host)
+		if err != nil {
+			result.Status = db.StatusDown
+			result.Error = fmt.Sprintf("Invalid TLS config: %v", err)
+			return result
+		}
+	}
+
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+
+	start := time.Now()
+	conn, err := (&net.Dialer{Deadline: deadline}).Dial("tcp", monitor.Target)
+	connectTime := time.Since(start)
+
+	result.ResponseTimeMs = int(connectTime.Milliseconds())
+	result.Details["connect_time_ms"] = connectTime.Milliseconds()
+
+	if err != nil {
+		result.Status = db.StatusDown
+		result.Error = fmt.Sprintf("Connection failed: %v", err)
+		return result
+	}
+	defer conn.Close()
+	conn.SetDeadline(deadline)
+
+	if tlsConfig != nil {
+		handshakeStart := time.Now()
+		tlsConn := tls.Client(conn, tlsConfig)
+		err := tlsConn.Handshake()
+		result.Details["tls_handshake_ms"] = time.Since(handshakeStart).Milliseconds()
+		result.ResponseTimeMs = int(time.Since(start).Milliseconds())
+		if err != nil {
+			result.Status = db.StatusDown
+			result.Error = fmt.Sprintf("TLS handshake failed: %v", err)
+			return result
+		}
+		conn = tlsConn
+	}
+
+	if monitor.Config.TCPSend != "" {
+		if _, err := conn.Write([]byte(monitor.Config.TCPSend)); err != nil {
+			result.Status = db.StatusDown
+			result.Error = fmt.Sprintf("Failed to send payload: %v", err)
+			return result
+		}
+	}
+
+	if monitor.Config.TCPExpect == "" && expect == nil {
+		result.Status = db.StatusUp
+		return result
+	}
+
+	// Read until the expectation matches, the server stops sending or the timeout
+	matches := func(response []byte) bool {
+		if monitor.Config.TCPExpect != "" && !bytes.Contains(response, []byte(monitor.Config.TCPExpect)) {
+			return false
+		}
+		return expect == nil || expect.Match(response)
+	}
+
+	var response []byte
+	buf := make([]byte, 1024)
+	matched := false
+	for len(response) < maxTCPResponse {
+		n, err := conn.Read(buf)
+		response = append(response, buf[:n]...)
+		if matched = matches(response); matched || err != nil {
+			break
+		}
+	}
+	if len(response) > maxTCPResponse {
+		response = response[:maxTCPResponse]
+	}
+
+	result.ResponseTimeMs = int(time.Since(start).Milliseconds())
+	result.Details["response"] = truncate(strings.ToValidUTF8(string(response), ""), 512)
+
+	if !matched {
+		result.Status = db.StatusDown
+		if len(response) == 0 {
+			result.Error = "No response received"
+		} else {
+			result.Error = "Expected response not received"
+		}
+		return result
+	}
+
+	result.Status = db.StatusUp
+	return result
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
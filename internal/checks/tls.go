@@ -0,0 +1,74 @@
+package checks
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// buildTLSConfig returns the client TLS config for a monitor's TLS options.
+// host is the server name verified when the options do not override it.
+func buildTLSConfig(options *db.TLSConfig, host string) (*tls.Config, error) {
+	config := &tls.Config{ServerName: host}
+	if options == nil {
+		return config, nil
+	}
+
+	if options.ServerName != "" {
+		config.ServerName = options.ServerName
+	}
+	config.InsecureSkipVerify = options.InsecureSkipVerify
+
+	if options.MinVersion != "" {
+		version, ok := tlsVersions[options.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("invalid min TLS version %q, use 1.0, 1.1, 1.2 or 1.3", options.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	if options.CACert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(options.CACert)) {
+			return nil, fmt.Errorf("no valid certificates found in CA bundle")
+		}
+		config.RootCAs = pool
+	}
+
+	if options.ClientCert != "" || options.ClientKey != "" {
+		cert, err := tls.X509KeyPair([]byte(options.ClientCert), []byte(options.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// ValidateTLSConfig checks that a monitor's TLS options can be loaded.
+// Certificates and keys referencing secrets are left to the worker.
+func ValidateTLSConfig(options *db.TLSConfig) error {
+	if options == nil {
+		return nil
+	}
+	checked := *options
+	if secrets.HasReferences(checked.ClientCert) || secrets.HasReferences(checked.ClientKey) {
+		checked.ClientCert, checked.ClientKey = "", ""
+	}
+	if secrets.HasReferences(checked.CACert) {
+		checked.CACert = ""
+	}
+	_, err := buildTLSConfig(&checked, "")
+	return err
+}
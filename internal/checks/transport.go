@@ -0,0 +1,107 @@
+package checks
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+)
+
+// maxCachedTransports bounds how many TLS/proxy combinations are kept. The
+// cache starts over when it is full, which only costs rebuilding transports.
+const maxCachedTransports = 256
+
+// transportCache hands out one transport per combination of TLS options and
+// proxy, so certificates are parsed once and monitors with the same options
+// share a transport. Monitors without either use the base transport.
+type transportCache struct {
+	base *http.Transport
+
+	mu         sync.Mutex
+	transports map[[sha256.Size]byte]*http.Transport
+}
+
+func newTransportCache(base *http.Transport) *transportCache {
+	return &transportCache{
+		base:       base,
+		transports: make(map[[sha256.Size]byte]*http.Transport),
+	}
+}
+
+func (c *transportCache) get(options *db.TLSConfig, proxyURL string) (*http.Transport, error) {
+	if options == nil && proxyURL == "" {
+		return c.base, nil
+	}
+
+	raw, err := json.Marshal(struct {
+		TLS   *db.TLSConfig
+		Proxy string
+	}{options, proxyURL})
+	if err != nil {
+		return nil, err
+	}
+	key := sha256.Sum256(raw)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if transport, ok := c.transports[key]; ok {
+		return transport, nil
+	}
+
+	transport := c.base.Clone()
+	if options != nil {
+		// No server name here; the transport verifies each request's host
+		tlsConfig, err := buildTLSConfig(options, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS config: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	if proxyURL != "" {
+		proxy, err := parseProxyURL(proxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if len(c.transports) >= maxCachedTransports {
+		for _, old := range c.transports {
+			old.CloseIdleConnections()
+		}
+		c.transports = make(map[[sha256.Size]byte]*http.Transport)
+	}
+	c.transports[key] = transport
+	return transport, nil
+}
+
+func parseProxyURL(proxyURL string) (*url.URL, error) {
+	proxy, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy URL scheme must be http, https, socks5 or socks5h")
+	}
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("proxy URL must include a host")
+	}
+	return proxy, nil
+}
+
+// ValidateProxyURL checks that a monitor's proxy URL can be used
+func ValidateProxyURL(proxyURL string) error {
+	if proxyURL == "" || secrets.HasReferences(proxyURL) {
+		return nil
+	}
+	_, err := parseProxyURL(proxyURL)
+	return err
+}
@@ -2,18 +2,21 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	Keycloak  KeycloakConfig
-	Mimir     MimirConfig
-	Scheduler SchedulerConfig
-	Regions   map[string]RegionConfig
+	Server        ServerConfig
+	Database      DatabaseConfig
+	Keycloak      KeycloakConfig
+	Mimir         MimirConfig
+	Scheduler     SchedulerConfig
+	Notifications NotificationsConfig
+	Secrets       SecretsConfig
+	Regions       map[string]RegionConfig
 }
 
 type ServerConfig struct {
@@ -48,6 +51,51 @@ type SchedulerConfig struct {
 	MaxRetries   int
 }
 
+type NotificationsConfig struct {
+	SendTimeout time.Duration
+	// DashboardURL is used to build links back to monitors in alerts
+	DashboardURL string
+	SMTP         SMTPConfig
+	// Base URLs of the incident management APIs, overridable for testing against a local stand-in
+	PagerDutyURL string
+	OpsgenieURL  string
+	Outbox       OutboxConfig
+	Escalation   EscalationConfig
+}
+
+// OutboxConfig controls how queued notifications are delivered and retried
+type OutboxConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	// Delay before the first retry, doubled on every further attempt up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// EscalationConfig controls how often unacknowledged incidents are checked for due escalation steps
+type EscalationConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// TLSMode is one of "starttls", "tls" (implicit) or "none"
+	TLSMode            string
+	InsecureSkipVerify bool
+}
+
+// SecretsConfig holds the master key encrypting tenant secrets: 32 random bytes
+// encoded as base64 (e.g. openssl rand -base64 32). Without it secrets are disabled.
+type SecretsConfig struct {
+	MasterKey string
+}
+
 type RegionConfig struct {
 	Name     string
 	Location string
@@ -73,6 +121,19 @@ func Load() (*Config, error) {
 	viper.SetDefault("scheduler.workercount", 10)
 	viper.SetDefault("scheduler.checktimeout", "30s")
 	viper.SetDefault("scheduler.maxretries", 3)
+	viper.SetDefault("notifications.sendtimeout", "10s")
+	viper.SetDefault("notifications.smtp.port", 587)
+	viper.SetDefault("notifications.smtp.tlsmode", "starttls")
+	viper.SetDefault("notifications.smtp.from", "Uptime Guardian <alerts@uptime-guardian.local>")
+	viper.SetDefault("notifications.pagerdutyurl", "https://events.pagerduty.com")
+	viper.SetDefault("notifications.opsgenieurl", "https://api.opsgenie.com")
+	viper.SetDefault("notifications.outbox.pollinterval", "5s")
+	viper.SetDefault("notifications.outbox.batchsize", 50)
+	viper.SetDefault("notifications.outbox.maxattempts", 8)
+	viper.SetDefault("notifications.outbox.initialbackoff", "30s")
+	viper.SetDefault("notifications.outbox.maxbackoff", "1h")
+	viper.SetDefault("notifications.escalation.pollinterval", "15s")
+	viper.SetDefault("notifications.escalation.batchsize", 100)
 
 	var cfg Config
 	if err := viper.ReadInConfig(); err != nil {
@@ -98,6 +159,38 @@ func Load() (*Config, error) {
 	if token := os.Getenv("MIMIR_AUTH_TOKEN"); token != "" {
 		cfg.Mimir.AuthToken = token
 	}
+	if url := os.Getenv("DASHBOARD_URL"); url != "" {
+		cfg.Notifications.DashboardURL = url
+	}
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		cfg.Notifications.SMTP.Host = host
+	}
+	if port := os.Getenv("SMTP_PORT"); port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			cfg.Notifications.SMTP.Port = p
+		}
+	}
+	if user := os.Getenv("SMTP_USERNAME"); user != "" {
+		cfg.Notifications.SMTP.Username = user
+	}
+	if password := os.Getenv("SMTP_PASSWORD"); password != "" {
+		cfg.Notifications.SMTP.Password = password
+	}
+	if from := os.Getenv("SMTP_FROM"); from != "" {
+		cfg.Notifications.SMTP.From = from
+	}
+	if mode := os.Getenv("SMTP_TLS_MODE"); mode != "" {
+		cfg.Notifications.SMTP.TLSMode = mode
+	}
+	if url := os.Getenv("PAGERDUTY_API_URL"); url != "" {
+		cfg.Notifications.PagerDutyURL = url
+	}
+	if url := os.Getenv("OPSGENIE_API_URL"); url != "" {
+		cfg.Notifications.OpsgenieURL = url
+	}
+	if key := os.Getenv("SECRETS_MASTER_KEY"); key != "" {
+		cfg.Secrets.MasterKey = key
+	}
 
 	// Default regions if not configured
 	if len(cfg.Regions) == 0 {
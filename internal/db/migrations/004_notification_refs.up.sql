@@ -0,0 +1,5 @@
+-- External message references (e.g. Slack thread ts) keyed by notification channel
+ALTER TABLE
+    incidents
+ADD
+    COLUMN notification_refs JSONB NOT NULL DEFAULT '{}' :: jsonb;
@@ -0,0 +1,20 @@
+-- Additional chat integrations
+ALTER TABLE
+    notification_channels DROP CONSTRAINT IF EXISTS notification_channels_type_check;
+
+ALTER TABLE
+    notification_channels
+ADD
+    CONSTRAINT notification_channels_type_check CHECK (
+        type IN (
+            'webhook',
+            'email',
+            'slack',
+            'pagerduty',
+            'opsgenie',
+            'teams',
+            'discord',
+            'telegram',
+            'googlechat'
+        )
+    );
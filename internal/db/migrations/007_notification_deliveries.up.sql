@@ -0,0 +1,29 @@
+-- Notification outbox: every delivery attempt is persisted and retried with backoff
+CREATE TABLE notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    channel_type VARCHAR(50) NOT NULL,
+    channel JSONB NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    -- Deliveries sharing a dedup key and channel are delivered in creation order
+    dedup_key VARCHAR(255) NOT NULL,
+    monitor_id UUID REFERENCES monitors(id) ON DELETE CASCADE,
+    group_id UUID REFERENCES monitor_groups(id) ON DELETE CASCADE,
+    incident_id UUID REFERENCES incidents(id) ON DELETE SET NULL,
+    group_incident_id UUID REFERENCES monitor_group_incidents(id) ON DELETE SET NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP
+);
+
+-- Create indexes for notification_deliveries
+CREATE INDEX idx_notification_deliveries_due ON notification_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_notification_deliveries_tenant ON notification_deliveries(tenant_id, created_at DESC);
+CREATE INDEX idx_notification_deliveries_incident ON notification_deliveries(incident_id);
+CREATE INDEX idx_notification_deliveries_dedup ON notification_deliveries(dedup_key, created_at) WHERE status = 'pending';
@@ -0,0 +1,17 @@
+-- Custom alert titles and bodies. A template applies to the whole tenant or to one
+-- channel, and to every event or a single one; the most specific match wins.
+CREATE TABLE notification_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    channel_id UUID REFERENCES notification_channels(id) ON DELETE CASCADE,
+    event VARCHAR(50),
+    title TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Only one template per tenant, channel and event
+CREATE UNIQUE INDEX idx_notification_templates_scope ON notification_templates(
+    tenant_id, COALESCE(channel_id::text, ''), COALESCE(event, '')
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_notification_deliveries_batch;
+
+ALTER TABLE notification_deliveries DROP COLUMN IF EXISTS batch_key;
+
+ALTER TABLE notification_channels
+    DROP COLUMN IF EXISTS aggregation_window,
+    DROP COLUMN IF EXISTS digest;
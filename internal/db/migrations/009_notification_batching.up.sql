@@ -0,0 +1,10 @@
+-- Per-channel batching: alerts are held for aggregation_window seconds and sent as one
+-- message, or collected into an hourly/daily digest
+ALTER TABLE notification_channels
+    ADD COLUMN aggregation_window INTEGER NOT NULL DEFAULT 0 CHECK (aggregation_window >= 0),
+    ADD COLUMN digest VARCHAR(20) NOT NULL DEFAULT '' CHECK (digest IN ('', 'hourly', 'daily'));
+
+-- Deliveries sharing a batch key are sent together once they are due
+ALTER TABLE notification_deliveries ADD COLUMN batch_key VARCHAR(255);
+
+CREATE INDEX idx_notification_deliveries_batch ON notification_deliveries(batch_key) WHERE status = 'pending';
@@ -0,0 +1,3 @@
+ALTER TABLE notification_channels
+    DROP COLUMN IF EXISTS min_severity,
+    DROP COLUMN IF EXISTS schedule;
@@ -0,0 +1,4 @@
+-- Per-channel routing: minimum incident severity and a delivery window (quiet hours)
+ALTER TABLE notification_channels
+    ADD COLUMN min_severity VARCHAR(20) NOT NULL DEFAULT '' CHECK (min_severity IN ('', 'critical', 'warning', 'info')),
+    ADD COLUMN schedule JSONB;
@@ -0,0 +1,11 @@
+ALTER TABLE monitor_group_incidents
+    DROP COLUMN IF EXISTS escalation_policy_id,
+    DROP COLUMN IF EXISTS escalation_step,
+    DROP COLUMN IF EXISTS next_escalation_at;
+
+ALTER TABLE incidents
+    DROP COLUMN IF EXISTS escalation_policy_id,
+    DROP COLUMN IF EXISTS escalation_step,
+    DROP COLUMN IF EXISTS next_escalation_at;
+
+DROP TABLE IF EXISTS escalation_policies;
@@ -0,0 +1,28 @@
+-- Escalation policies notify a further set of channels after each delay while an
+-- incident stays unacknowledged
+CREATE TABLE escalation_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    steps JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_escalation_policies_tenant ON escalation_policies(tenant_id);
+
+-- escalation_step counts the steps already notified; next_escalation_at is cleared
+-- once the incident is acknowledged, resolved or the last step went out
+ALTER TABLE incidents
+    ADD COLUMN escalation_policy_id UUID REFERENCES escalation_policies(id) ON DELETE SET NULL,
+    ADD COLUMN escalation_step INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN next_escalation_at TIMESTAMP;
+
+ALTER TABLE monitor_group_incidents
+    ADD COLUMN escalation_policy_id UUID REFERENCES escalation_policies(id) ON DELETE SET NULL,
+    ADD COLUMN escalation_step INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN next_escalation_at TIMESTAMP;
+
+CREATE INDEX idx_incidents_next_escalation ON incidents(next_escalation_at) WHERE next_escalation_at IS NOT NULL;
+CREATE INDEX idx_group_incidents_next_escalation ON monitor_group_incidents(next_escalation_at) WHERE next_escalation_at IS NOT NULL;
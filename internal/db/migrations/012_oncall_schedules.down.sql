@@ -0,0 +1,24 @@
+DELETE FROM notification_channels WHERE type = 'oncall';
+
+ALTER TABLE
+    notification_channels DROP CONSTRAINT IF EXISTS notification_channels_type_check;
+
+ALTER TABLE
+    notification_channels
+ADD
+    CONSTRAINT notification_channels_type_check CHECK (
+        type IN (
+            'webhook',
+            'email',
+            'slack',
+            'pagerduty',
+            'opsgenie',
+            'teams',
+            'discord',
+            'telegram',
+            'googlechat'
+        )
+    );
+
+DROP TABLE IF EXISTS oncall_overrides;
+DROP TABLE IF EXISTS oncall_schedules;
@@ -0,0 +1,50 @@
+-- On-call schedules: rotation layers over the schedule's members, each member with
+-- the contact channels they are paged through
+CREATE TABLE oncall_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    members JSONB NOT NULL DEFAULT '[]',
+    layers JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_oncall_schedules_tenant ON oncall_schedules(tenant_id);
+
+-- Ad-hoc overrides put a member on call for a period, above every layer
+CREATE TABLE oncall_overrides (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    schedule_id UUID NOT NULL REFERENCES oncall_schedules(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL CHECK (ends_at > starts_at),
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_oncall_overrides_schedule ON oncall_overrides(schedule_id, ends_at);
+
+-- Channels that page whoever is on call for a schedule
+ALTER TABLE
+    notification_channels DROP CONSTRAINT IF EXISTS notification_channels_type_check;
+
+ALTER TABLE
+    notification_channels
+ADD
+    CONSTRAINT notification_channels_type_check CHECK (
+        type IN (
+            'webhook',
+            'email',
+            'slack',
+            'pagerduty',
+            'opsgenie',
+            'teams',
+            'discord',
+            'telegram',
+            'googlechat',
+            'oncall'
+        )
+    );
@@ -0,0 +1,7 @@
+ALTER TABLE
+    monitors DROP CONSTRAINT IF EXISTS monitors_type_check;
+
+ALTER TABLE
+    monitors
+ADD
+    CONSTRAINT monitors_type_check CHECK (type IN ('http', 'ssl', 'dns', 'domain', 'tcp', 'ping'));
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS heartbeat_pings;
+
+DROP INDEX IF EXISTS idx_monitors_heartbeat_token;
+
+ALTER TABLE monitors DROP COLUMN IF EXISTS heartbeat_token;
+
+ALTER TABLE
+    monitors DROP CONSTRAINT IF EXISTS monitors_type_check;
+
+ALTER TABLE
+    monitors
+ADD
+    CONSTRAINT monitors_type_check CHECK (
+        type IN ('http', 'ssl', 'dns', 'domain', 'tcp', 'ping', 'grpc')
+    );
@@ -0,0 +1,36 @@
+-- Heartbeat monitors are pinged by the jobs they watch instead of being checked
+ALTER TABLE
+    monitors DROP CONSTRAINT IF EXISTS monitors_type_check;
+
+ALTER TABLE
+    monitors
+ADD
+    CONSTRAINT monitors_type_check CHECK (
+        type IN (
+            'http',
+            'ssl',
+            'dns',
+            'domain',
+            'tcp',
+            'ping',
+            'grpc',
+            'heartbeat'
+        )
+    );
+
+ALTER TABLE monitors
+    ADD COLUMN heartbeat_token VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_monitors_heartbeat_token ON monitors(heartbeat_token) WHERE heartbeat_token IS NOT NULL;
+
+CREATE TABLE heartbeat_pings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    monitor_id UUID NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('up', 'down')),
+    duration_ms INTEGER,
+    message TEXT NOT NULL DEFAULT '',
+    source_ip VARCHAR(64) NOT NULL DEFAULT '',
+    received_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_heartbeat_pings_monitor ON heartbeat_pings(monitor_id, received_at DESC);
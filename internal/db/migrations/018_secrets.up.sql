@@ -0,0 +1,14 @@
+-- Tenant secrets referenced from monitor and channel configs as {{secret "name"}}.
+-- Each value is encrypted with its own data key, stored encrypted by the master key.
+CREATE TABLE secrets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    encrypted_key BYTEA NOT NULL,
+    encrypted_value BYTEA NOT NULL,
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, name)
+);
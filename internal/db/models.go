@@ -9,10 +9,15 @@ import (
 type MonitorType string
 
 const (
-	MonitorTypeHTTP   MonitorType = "http"
-	MonitorTypeSSL    MonitorType = "ssl"
-	MonitorTypeDNS    MonitorType = "dns"
-	MonitorTypeDomain MonitorType = "domain"
+	MonitorTypeHTTP      MonitorType = "http"
+	MonitorTypeSSL       MonitorType = "ssl"
+	MonitorTypeDNS       MonitorType = "dns"
+	MonitorTypeDomain    MonitorType = "domain"
+	MonitorTypeTCP       MonitorType = "tcp"
+	MonitorTypePing      MonitorType = "ping"
+	MonitorTypeGRPC      MonitorType = "grpc"
+	MonitorTypeHeartbeat MonitorType = "heartbeat"
+	MonitorTypeHTTPFlow  MonitorType = "http_flow"
 )
 
 type CheckStatus string
@@ -39,6 +44,9 @@ type Monitor struct {
 	CreatedAt        time.Time          `json:"created_at" db:"created_at"`
 	UpdatedAt        time.Time          `json:"updated_at" db:"updated_at"`
 	CreatedBy        string             `json:"created_by" db:"created_by"`
+
+	// HeartbeatToken is the secret of a heartbeat monitor's ping URL, /ping/{token}
+	HeartbeatToken *string `json:"heartbeat_token,omitempty" db:"heartbeat_token"`
 }
 
 type MonitorConfig struct {
@@ -49,7 +57,10 @@ type MonitorConfig struct {
 	ExpectedStatusCodes []int             `json:"expected_status_codes,omitempty"`
 	SearchString        string            `json:"search_string,omitempty"`
 	BasicAuth           *BasicAuth        `json:"basic_auth,omitempty"`
-	FollowRedirects     bool              `json:"follow_redirects,omitempty"`
+	OAuth2              *OAuth2Config     `json:"oauth2,omitempty"`
+	FollowRedirects     *bool             `json:"follow_redirects,omitempty"` // Unset follows redirects
+	Assertions          []HTTPAssertion   `json:"assertions,omitempty"`
+	ProxyURL            string            `json:"proxy_url,omitempty"` // http, https or socks5
 
 	// SSL Check
 	CheckExpiry         bool `json:"check_expiry,omitempty"`
@@ -61,6 +72,129 @@ type MonitorConfig struct {
 
 	// Domain Check
 	DomainMinDaysBeforeExpiry int `json:"domain_min_days_before_expiry,omitempty"`
+
+	// TCP Check. TCPSend is written once connected; TCPExpect and TCPExpectRegex
+	// are matched against what the server sends back (e.g. its banner).
+	TCPSend        string `json:"tcp_send,omitempty"`
+	TCPExpect      string `json:"tcp_expect,omitempty"`
+	TCPExpectRegex string `json:"tcp_expect_regex,omitempty"`
+	TCPTLS         bool   `json:"tcp_tls,omitempty"`
+
+	// Ping Check. The check is degraded when packet loss (percent) or the average
+	// round-trip time exceed the thresholds, and down when no reply arrives.
+	PingCount         int     `json:"ping_count,omitempty"`
+	PingMaxPacketLoss float64 `json:"ping_max_packet_loss,omitempty"`
+	PingMaxLatencyMs  int     `json:"ping_max_latency_ms,omitempty"`
+
+	// gRPC Check. GRPCService is the name sent to grpc.health.v1.Health/Check,
+	// empty for the server's overall health. Plaintext unless GRPCTLS is set.
+	GRPCService  string            `json:"grpc_service,omitempty"`
+	GRPCTLS      bool              `json:"grpc_tls,omitempty"`
+	GRPCMetadata map[string]string `json:"grpc_metadata,omitempty"`
+
+	// Heartbeat. The monitor is down when no ping arrived within the interval plus
+	// HeartbeatGracePeriod seconds, or when the last ping reported a failure.
+	HeartbeatGracePeriod int `json:"heartbeat_grace_period,omitempty"`
+
+	// HTTP flow. Steps run in order against the target as base URL, sharing
+	// cookies and the variables extracted from earlier responses.
+	Steps []HTTPFlowStep `json:"steps,omitempty"`
+
+	// TLS options for checks connecting over TLS, including HTTP checks of https URLs
+	TLS *TLSConfig `json:"tls,omitempty"`
+}
+
+// TLSConfig customizes how a check verifies the server and authenticates to it.
+// Certificates and keys are PEM encoded.
+type TLSConfig struct {
+	ClientCert         string `json:"client_cert,omitempty"`
+	ClientKey          string `json:"client_key,omitempty"`
+	CACert             string `json:"ca_cert,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+	MinVersion         string `json:"min_version,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+// HTTPFlowStep is one request of an http_flow monitor. URL, Headers and Body can
+// use {{name}} to insert variables extracted by earlier steps.
+type HTTPFlowStep struct {
+	Name    string            `json:"name"`
+	Method  string            `json:"method,omitempty"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+
+	// Assertions. Any 2xx status passes when ExpectedStatusCodes is empty; a step
+	// slower than MaxResponseTimeMs degrades the check without stopping the flow.
+	ExpectedStatusCodes []int  `json:"expected_status_codes,omitempty"`
+	SearchString        string `json:"search_string,omitempty"`
+	MaxResponseTimeMs   int    `json:"max_response_time_ms,omitempty"`
+
+	Assertions []HTTPAssertion   `json:"assertions,omitempty"`
+	Extract    []HTTPFlowExtract `json:"extract,omitempty"`
+}
+
+// HTTPFlowExtract stores a value of a step's response in a variable. From is
+// "json" (Path is a JSONPath), "header" (Path is the header name) or "regex"
+// (Path is a regular expression; its first group, or the whole match, is used).
+type HTTPFlowExtract struct {
+	Variable string `json:"var"`
+	From     string `json:"from"`
+	Path     string `json:"path"`
+}
+
+// Sources HTTPFlowExtract can read from
+const (
+	ExtractFromJSON   = "json"
+	ExtractFromHeader = "header"
+	ExtractFromRegex  = "regex"
+)
+
+// HTTPAssertion is checked against an HTTP response. Source picks what is
+// checked: a JSONPath ("json", Path is the JSONPath), the body, a header (Path
+// is its name), the body size in bytes, the response time in milliseconds or
+// the URL the redirects ended on.
+// A failed assertion marks the check down unless Severity is "degraded".
+type HTTPAssertion struct {
+	Source   string `json:"source"`
+	Path     string `json:"path,omitempty"`
+	Operator string `json:"operator"`
+	Value    string `json:"value,omitempty"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// Sources HTTPAssertion can check
+const (
+	AssertJSON         = "json"
+	AssertBody         = "body"
+	AssertHeader       = "header"
+	AssertBodySize     = "body_size"
+	AssertResponseTime = "response_time"
+	AssertFinalURL     = "final_url"
+)
+
+// Operators HTTPAssertion can apply. Negated operators pass when nothing matches.
+const (
+	OpEquals      = "equals"
+	OpNotEquals   = "not_equals"
+	OpContains    = "contains"
+	OpNotContains = "not_contains"
+	OpMatches     = "matches"
+	OpNotMatches  = "not_matches"
+	OpExists      = "exists"
+	OpNotExists   = "not_exists"
+	OpLessThan    = "less_than"
+	OpGreaterThan = "greater_than"
+	OpIsHTTPS     = "is_https"
+)
+
+// OAuth2Config is an OAuth2 client (client credentials grant). Its access token
+// is sent as a bearer token and requested again once it expires.
+type OAuth2Config struct {
+	TokenURL     string   `json:"token_url"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes,omitempty"`
 }
 
 type BasicAuth struct {
@@ -73,12 +207,39 @@ type NotificationConfig struct {
 	OnFailureCount   int                   `json:"on_failure_count"`
 	OnRecovery       bool                  `json:"on_recovery"`
 	ReminderInterval int                   `json:"reminder_interval"`
+	// EscalationPolicyID escalates unacknowledged incidents beyond Channels
+	EscalationPolicyID string `json:"escalation_policy_id,omitempty"`
 }
 
+// NotificationChannel is a tenant-level channel stored in notification_channels.
+// Monitors, groups and alert rules reference stored channels with an entry that
+// only sets ID; entries with an inline Type/Config are still supported.
 type NotificationChannel struct {
-	Type    string                 `json:"type"`
-	Config  map[string]interface{} `json:"config"`
-	Enabled bool                   `json:"enabled"`
+	ID       string `json:"id,omitempty" db:"id"`
+	TenantID string `json:"-" db:"tenant_id"`
+	Name     string `json:"name,omitempty" db:"name"`
+	Type     string `json:"type,omitempty" db:"type"`
+	Config   JSONB  `json:"config,omitempty" db:"config"`
+	Enabled  bool   `json:"enabled" db:"enabled"`
+
+	// AggregationWindow holds alerts for this many seconds so concurrent ones go out
+	// as a single message. Digest ("hourly" or "daily") collects them into a periodic
+	// summary instead.
+	AggregationWindow int    `json:"aggregation_window,omitempty" db:"aggregation_window"`
+	Digest            string `json:"digest,omitempty" db:"digest"`
+
+	// MinSeverity drops alerts below "critical", "warning" or "info"; Schedule limits
+	// when alerts are delivered
+	MinSeverity string           `json:"min_severity,omitempty" db:"min_severity"`
+	Schedule    *ChannelSchedule `json:"schedule,omitempty" db:"schedule"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty" db:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// IsReference reports whether the entry points to a stored channel instead of carrying its config
+func (nc NotificationChannel) IsReference() bool {
+	return nc.ID != "" && nc.Type == ""
 }
 
 type CheckResult struct {
@@ -94,6 +255,17 @@ type CheckResult struct {
 	CheckedAt      time.Time   `json:"checked_at" db:"checked_at"`
 }
 
+// ConfigErrorDetail is set in the details of a check that could not run because
+// of the monitor's own configuration, e.g. credentials that were rejected
+const ConfigErrorDetail = "config_error"
+
+// IsConfigError reports whether the check failed on its configuration rather
+// than on the target. Such results do not open, update or resolve incidents.
+func (r *CheckResult) IsConfigError() bool {
+	flag, _ := r.Details[ConfigErrorDetail].(bool)
+	return flag
+}
+
 type MonitorStatus struct {
 	MonitorID      string      `json:"monitor_id" db:"monitor_id"`
 	Status         CheckStatus `json:"status" db:"status"`
@@ -155,6 +327,43 @@ func (nc *NotificationConfig) Scan(value interface{}) error {
 	return json.Unmarshal(value.([]byte), nc)
 }
 
+// ChannelSchedule is the window in which a channel receives alerts, e.g. business
+// hours. Days and times are checked independently in Timezone; a window whose To is
+// before From spans midnight.
+type ChannelSchedule struct {
+	Timezone string   `json:"timezone,omitempty"` // IANA name, defaults to UTC
+	Days     []string `json:"days,omitempty"`     // "mon" ... "sun", empty means every day
+	From     string   `json:"from,omitempty"`     // "09:00"
+	To       string   `json:"to,omitempty"`       // "18:00"
+	// OutsideWindow is "queue" (deliver when the window opens, the default) or "drop"
+	OutsideWindow string `json:"outside_window,omitempty"`
+}
+
+func (cs ChannelSchedule) Value() (driver.Value, error) {
+	return json.Marshal(cs)
+}
+
+func (cs *ChannelSchedule) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	return json.Unmarshal(value.([]byte), cs)
+}
+
+// ChannelSnapshot stores a resolved NotificationChannel in a single JSONB column
+type ChannelSnapshot NotificationChannel
+
+func (cs ChannelSnapshot) Value() (driver.Value, error) {
+	return json.Marshal(NotificationChannel(cs))
+}
+
+func (cs *ChannelSnapshot) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	return json.Unmarshal(value.([]byte), (*NotificationChannel)(cs))
+}
+
 // Adicione após as structs existentes
 
 type MonitorSLO struct {
@@ -198,6 +407,12 @@ type Incident struct {
 	ResolutionNotes   *string    `json:"resolution_notes" db:"resolution_notes"`
 	AcknowledgedAt    *time.Time `json:"acknowledged_at" db:"acknowledged_at"`
 	AcknowledgedBy    *string    `json:"acknowledged_by" db:"acknowledged_by"`
+	NotificationRefs  JSONB      `json:"-" db:"notification_refs"`
+
+	// Escalation state, see EscalationPolicy
+	EscalationPolicyID *string    `json:"escalation_policy_id,omitempty" db:"escalation_policy_id"`
+	EscalationStep     int        `json:"escalation_step" db:"escalation_step"`
+	NextEscalationAt   *time.Time `json:"next_escalation_at,omitempty" db:"next_escalation_at"`
 }
 
 type IncidentEvent struct {
@@ -217,6 +432,7 @@ const (
 	IncidentEventInvestigating = "investigating"
 	IncidentEventResolved      = "resolved"
 	IncidentEventComment       = "comment"
+	IncidentEventEscalated     = "escalated"
 )
 
 type IncidentFilters struct {
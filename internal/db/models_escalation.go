@@ -0,0 +1,49 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// EscalationPolicy notifies one step after another while an incident stays
+// unacknowledged. Monitors and groups attach a policy through
+// NotificationConfig.EscalationPolicyID.
+type EscalationPolicy struct {
+	ID          string          `json:"id" db:"id"`
+	TenantID    string          `json:"-" db:"tenant_id"`
+	Name        string          `json:"name" db:"name"`
+	Description string          `json:"description" db:"description"`
+	Steps       EscalationSteps `json:"steps" db:"steps"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// EscalationStep is notified DelayMinutes after the previous step, or after the
+// incident's first alert for the first step
+type EscalationStep struct {
+	DelayMinutes int                   `json:"delay_minutes"`
+	Channels     []NotificationChannel `json:"channels"`
+}
+
+type EscalationSteps []EscalationStep
+
+func (es EscalationSteps) Value() (driver.Value, error) {
+	if es == nil {
+		return json.Marshal([]EscalationStep{})
+	}
+	return json.Marshal([]EscalationStep(es))
+}
+
+func (es *EscalationSteps) Scan(value interface{}) error {
+	if value == nil {
+		*es = EscalationSteps{}
+		return nil
+	}
+	return json.Unmarshal(value.([]byte), es)
+}
+
+// Delay returns how long to wait before notifying step i
+func (ep *EscalationPolicy) Delay(i int) time.Duration {
+	return time.Duration(ep.Steps[i].DelayMinutes) * time.Minute
+}
@@ -118,6 +118,9 @@ type MonitorGroupIncident struct {
 	HealthScoreAtStart *float64    `json:"health_score_at_start" db:"health_score_at_start"`
 	AcknowledgedAt     *time.Time  `json:"acknowledged_at" db:"acknowledged_at"`
 	AcknowledgedBy     *string     `json:"acknowledged_by" db:"acknowledged_by"`
+	EscalationPolicyID *string     `json:"escalation_policy_id,omitempty" db:"escalation_policy_id"`
+	EscalationStep     int         `json:"escalation_step" db:"escalation_step"`
+	NextEscalationAt   *time.Time  `json:"next_escalation_at,omitempty" db:"next_escalation_at"`
 }
 
 // MonitorGroupSLAReport represents SLA report for a group
@@ -0,0 +1,14 @@
+package db
+
+import "time"
+
+// HeartbeatPing is recorded each time a job hits a heartbeat monitor's ping URL
+type HeartbeatPing struct {
+	ID         string      `json:"id" db:"id"`
+	MonitorID  string      `json:"monitor_id" db:"monitor_id"`
+	Status     CheckStatus `json:"status" db:"status"`
+	DurationMs *int        `json:"duration_ms,omitempty" db:"duration_ms"`
+	Message    string      `json:"message,omitempty" db:"message"`
+	SourceIP   string      `json:"source_ip" db:"source_ip"`
+	ReceivedAt time.Time   `json:"received_at" db:"received_at"`
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// NotificationDelivery is an outbox entry for a single notification sent through one channel
+type NotificationDelivery struct {
+	ID          string `json:"id" db:"id"`
+	TenantID    string `json:"-" db:"tenant_id"`
+	ChannelType string `json:"channel_type" db:"channel_type"`
+	// The channel config may carry credentials and is never returned by the API
+	Channel         ChannelSnapshot `json:"-" db:"channel"`
+	Event           string          `json:"event" db:"event"`
+	DedupKey        string          `json:"dedup_key" db:"dedup_key"`
+	MonitorID       *string         `json:"monitor_id,omitempty" db:"monitor_id"`
+	GroupID         *string         `json:"group_id,omitempty" db:"group_id"`
+	IncidentID      *string         `json:"incident_id,omitempty" db:"incident_id"`
+	GroupIncidentID *string         `json:"group_incident_id,omitempty" db:"group_incident_id"`
+	BatchKey        *string         `json:"batch_key,omitempty" db:"batch_key"`
+	Payload         json.RawMessage `json:"payload" db:"payload"`
+	Status          DeliveryStatus  `json:"status" db:"status"`
+	Attempts        int             `json:"attempts" db:"attempts"`
+	MaxAttempts     int             `json:"max_attempts" db:"max_attempts"`
+	NextAttemptAt   time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError       *string         `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+	DeliveredAt     *time.Time      `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+type DeliveryFilters struct {
+	TenantID    string
+	Status      string
+	ChannelType string
+	MonitorID   string
+	IncidentID  string
+	Limit       int
+	Offset      int
+}
+
+// NotificationTemplate customizes alert titles and bodies. ChannelID and Event narrow
+// the scope; when they are nil the template applies to every channel or event.
+type NotificationTemplate struct {
+	ID        string    `json:"id" db:"id"`
+	TenantID  string    `json:"-" db:"tenant_id"`
+	ChannelID *string   `json:"channel_id,omitempty" db:"channel_id"`
+	Event     *string   `json:"event,omitempty" db:"event"`
+	Title     string    `json:"title" db:"title"`
+	Body      string    `json:"body" db:"body"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
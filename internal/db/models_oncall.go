@@ -0,0 +1,105 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// OnCallSchedule decides who is on call at any time. Layers rotate through the
+// schedule's members; the last layer with someone on call wins, and overrides win
+// over every layer. Notification channels of type "oncall" page whoever is on call
+// through that member's contact channels.
+type OnCallSchedule struct {
+	ID          string        `json:"id" db:"id"`
+	TenantID    string        `json:"-" db:"tenant_id"`
+	Name        string        `json:"name" db:"name"`
+	Description string        `json:"description" db:"description"`
+	Timezone    string        `json:"timezone" db:"timezone"` // IANA name, handoffs and restrictions use it
+	Members     OnCallMembers `json:"members" db:"members"`
+	Layers      OnCallLayers  `json:"layers" db:"layers"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at" db:"updated_at"`
+	// Computed fields
+	Overrides []*OnCallOverride `json:"overrides,omitempty" db:"-"`
+}
+
+// OnCallMember is a person taking part in a schedule and how to page them
+type OnCallMember struct {
+	User     string                `json:"user"` // e.g. the user's email
+	Name     string                `json:"name,omitempty"`
+	Channels []NotificationChannel `json:"channels"`
+}
+
+// OnCallLayer rotates through Users, handing off every day or week at the time of
+// day of Start. Days/From/To restrict the layer to part of the week, e.g. business hours.
+type OnCallLayer struct {
+	Name     string    `json:"name"`
+	Rotation string    `json:"rotation"` // "daily" or "weekly"
+	Start    time.Time `json:"start"`
+	Users    []string  `json:"users"`
+	Days     []string  `json:"days,omitempty"`
+	From     string    `json:"from,omitempty"`
+	To       string    `json:"to,omitempty"`
+}
+
+// OnCallOverride puts User on call from StartsAt to EndsAt regardless of the layers
+type OnCallOverride struct {
+	ID         string    `json:"id" db:"id"`
+	ScheduleID string    `json:"schedule_id" db:"schedule_id"`
+	User       string    `json:"user" db:"user_id"`
+	StartsAt   time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt     time.Time `json:"ends_at" db:"ends_at"`
+	CreatedBy  string    `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// Rotation types of an on-call layer
+const (
+	RotationDaily  = "daily"
+	RotationWeekly = "weekly"
+)
+
+// Member returns the member with the given user, nil if there is none
+func (s *OnCallSchedule) Member(user string) *OnCallMember {
+	for i := range s.Members {
+		if s.Members[i].User == user {
+			return &s.Members[i]
+		}
+	}
+	return nil
+}
+
+type OnCallMembers []OnCallMember
+
+func (m OnCallMembers) Value() (driver.Value, error) {
+	if m == nil {
+		return json.Marshal([]OnCallMember{})
+	}
+	return json.Marshal([]OnCallMember(m))
+}
+
+func (m *OnCallMembers) Scan(value interface{}) error {
+	if value == nil {
+		*m = OnCallMembers{}
+		return nil
+	}
+	return json.Unmarshal(value.([]byte), m)
+}
+
+type OnCallLayers []OnCallLayer
+
+func (l OnCallLayers) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]OnCallLayer{})
+	}
+	return json.Marshal([]OnCallLayer(l))
+}
+
+func (l *OnCallLayers) Scan(value interface{}) error {
+	if value == nil {
+		*l = OnCallLayers{}
+		return nil
+	}
+	return json.Unmarshal(value.([]byte), l)
+}
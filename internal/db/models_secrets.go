@@ -0,0 +1,21 @@
+package db
+
+import "time"
+
+// Secret is an encrypted credential of a tenant. Monitors and notification
+// channels reference it by name as {{secret "name"}}, which only the worker
+// resolves; the API never returns its value.
+type Secret struct {
+	ID          string `json:"id" db:"id"`
+	TenantID    string `json:"-" db:"tenant_id"`
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+
+	// The value encrypted by its own data key, which is encrypted by the master key
+	EncryptedKey   []byte `json:"-" db:"encrypted_key"`
+	EncryptedValue []byte `json:"-" db:"encrypted_value"`
+
+	CreatedBy string    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -36,11 +36,11 @@ func (r *Repository) CreateMonitor(m *Monitor) error {
         INSERT INTO monitors (
             id, tenant_id, name, type, target, enabled, 
             interval, timeout, regions, config, notification_config, 
-            tags, created_at, updated_at, created_by
+            tags, created_at, updated_at, created_by, heartbeat_token
         ) VALUES (
             :id, :tenant_id, :name, :type, :target, :enabled,
             :interval, :timeout, :regions, :config, :notification_config,
-            :tags, :created_at, :updated_at, :created_by
+            :tags, :created_at, :updated_at, :created_by, :heartbeat_token
         )`
 
 	_, err := r.db.NamedExec(query, m)
@@ -82,6 +82,7 @@ func (r *Repository) UpdateMonitor(m *Monitor) error {
             config = :config,
             notification_config = :notification_config,
             tags = :tags,
+            heartbeat_token = :heartbeat_token,
             updated_at = :updated_at
         WHERE id = :id AND tenant_id = :tenant_id`
 
@@ -132,10 +133,27 @@ func (r *Repository) GetMonitorsToCheck() ([]*Monitor, error) {
 	query := `
         SELECT m.* FROM monitors m
         LEFT JOIN monitor_last_status s ON m.id = s.monitor_id
+        LEFT JOIN LATERAL (
+            SELECT COALESCE(
+                (SELECT MAX(p.received_at) FROM heartbeat_pings p WHERE p.monitor_id = m.id),
+                m.created_at
+            ) + (m.interval || ' seconds')::interval
+              + (CASE WHEN COALESCE((m.config->>'heartbeat_grace_period')::int, 0) > 0
+                      THEN (m.config->>'heartbeat_grace_period')::int ELSE 60 END || ' seconds')::interval
+              AS ping_due
+        ) h ON m.type = 'heartbeat'
         WHERE m.enabled = true 
         AND (
             s.last_check IS NULL 
             OR s.last_check + (m.interval || ' seconds')::interval < NOW()
+            -- Heartbeats are evaluated as soon as a new ping arrives
+            OR (m.type = 'heartbeat' AND EXISTS (
+                SELECT 1 FROM heartbeat_pings p
+                WHERE p.monitor_id = m.id AND p.received_at > s.last_check
+            ))
+            -- and again once the next ping is overdue, the interval plus the grace
+            -- period (60 seconds by default) after the last one
+            OR (m.type = 'heartbeat' AND s.last_check < h.ping_due AND h.ping_due < NOW())
         )`
 
 	err := r.db.Select(&monitors, query)
@@ -234,6 +252,19 @@ func (r *Repository) GetCheckHistory(monitorID, tenantID string, limit int) ([]*
 	return results, err
 }
 
+func (r *Repository) GetCheckResult(id, tenantID string) (*CheckResult, error) {
+	var result CheckResult
+	query := `SELECT * FROM check_results WHERE id = $1 AND tenant_id = $2`
+	err := r.db.Get(&result, query, id, tenantID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("check result not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get check result: %w", err)
+	}
+	return &result, nil
+}
+
 // Ping checks database connection
 func (r *Repository) Ping() error {
 	return r.db.Ping()
@@ -248,10 +279,12 @@ func (r *Repository) CreateIncident(incident *Incident) error {
 	query := `
         INSERT INTO incidents (
             id, monitor_id, tenant_id, started_at, severity,
-            downtime_minutes, affected_checks, notifications_sent
+            downtime_minutes, affected_checks, notifications_sent,
+            notification_refs
         ) VALUES (
             :id, :monitor_id, :tenant_id, :started_at, :severity,
-            :downtime_minutes, :affected_checks, :notifications_sent
+            :downtime_minutes, :affected_checks, :notifications_sent,
+            :notification_refs
         )`
 
 	_, err := r.db.NamedExec(query, incident)
@@ -280,13 +313,15 @@ func (r *Repository) GetIncident(id, tenantID string) (*Incident, error) {
 	return &incident, err
 }
 
+// UpdateIncident saves the incident's state. notifications_sent and
+// notification_refs are left out as notifications update them concurrently,
+// see AddIncidentNotificationsSent and MergeIncidentNotificationRefs.
 func (r *Repository) UpdateIncident(incident *Incident) error {
 	query := `
         UPDATE incidents SET
             resolved_at = :resolved_at,
             downtime_minutes = :downtime_minutes,
             affected_checks = :affected_checks,
-            notifications_sent = :notifications_sent,
             root_cause = :root_cause,
             impact_description = :impact_description,
             resolution_notes = :resolution_notes,
@@ -0,0 +1,209 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Escalation policy operations
+
+func (r *Repository) CreateEscalationPolicy(p *EscalationPolicy) error {
+	query := `
+		INSERT INTO escalation_policies (
+			id, tenant_id, name, description, steps, created_at, updated_at
+		) VALUES (
+			:id, :tenant_id, :name, :description, :steps, :created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExec(query, p)
+	if err != nil {
+		return fmt.Errorf("failed to create escalation policy: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetEscalationPolicy(id, tenantID string) (*EscalationPolicy, error) {
+	var p EscalationPolicy
+	query := `SELECT * FROM escalation_policies WHERE id = $1 AND tenant_id = $2`
+	err := r.db.Get(&p, query, id, tenantID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("escalation policy not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get escalation policy: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *Repository) GetEscalationPoliciesByTenant(tenantID string) ([]*EscalationPolicy, error) {
+	policies := []*EscalationPolicy{}
+	query := `SELECT * FROM escalation_policies WHERE tenant_id = $1 ORDER BY name`
+	err := r.db.Select(&policies, query, tenantID)
+	return policies, err
+}
+
+func (r *Repository) UpdateEscalationPolicy(p *EscalationPolicy) error {
+	query := `
+		UPDATE escalation_policies SET
+			name = :name,
+			description = :description,
+			steps = :steps,
+			updated_at = :updated_at
+		WHERE id = :id AND tenant_id = :tenant_id`
+
+	_, err := r.db.NamedExec(query, p)
+	if err != nil {
+		return fmt.Errorf("failed to update escalation policy: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteEscalationPolicy(id, tenantID string) error {
+	result, err := r.db.Exec(`DELETE FROM escalation_policies WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete escalation policy: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("escalation policy not found")
+	}
+	return nil
+}
+
+// CountEscalationPolicyReferences counts monitors and groups the policy is attached to
+func (r *Repository) CountEscalationPolicyReferences(id, tenantID string) (int, error) {
+	var count int
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM monitors
+				WHERE tenant_id = $1 AND notification_config->>'escalation_policy_id' = $2)
+			+ (SELECT COUNT(*) FROM monitor_groups
+				WHERE tenant_id = $1 AND notification_config->>'escalation_policy_id' = $2)`
+
+	err := r.db.Get(&count, query, tenantID, id)
+	return count, err
+}
+
+// Incident escalation state. Only these methods write the escalation columns, so a
+// concurrent UpdateIncident with a stale copy never rewinds an escalation.
+
+// StartIncidentEscalation attaches the policy to the incident, first step due at firstStepAt
+func (r *Repository) StartIncidentEscalation(incidentID, policyID string, firstStepAt time.Time) error {
+	return r.startEscalation("incidents", incidentID, policyID, firstStepAt)
+}
+
+// StartGroupIncidentEscalation attaches the policy to the group incident, first step due at firstStepAt
+func (r *Repository) StartGroupIncidentEscalation(incidentID, policyID string, firstStepAt time.Time) error {
+	return r.startEscalation("monitor_group_incidents", incidentID, policyID, firstStepAt)
+}
+
+// GetDueIncidentEscalations returns unacknowledged open incidents whose next step is due
+func (r *Repository) GetDueIncidentEscalations(now time.Time, limit int) ([]*Incident, error) {
+	incidents := []*Incident{}
+	if err := r.db.Select(&incidents, dueEscalationsQuery("incidents"), now, limit); err != nil {
+		return nil, fmt.Errorf("failed to get due escalations: %w", err)
+	}
+	return incidents, nil
+}
+
+// GetDueGroupIncidentEscalations returns unacknowledged open group incidents whose next step is due
+func (r *Repository) GetDueGroupIncidentEscalations(now time.Time, limit int) ([]*MonitorGroupIncident, error) {
+	incidents := []*MonitorGroupIncident{}
+	if err := r.db.Select(&incidents, dueEscalationsQuery("monitor_group_incidents"), now, limit); err != nil {
+		return nil, fmt.Errorf("failed to get due group escalations: %w", err)
+	}
+	return incidents, nil
+}
+
+// AdvanceIncidentEscalation records that step was notified and schedules the next one
+// (nil when it was the last). It reports false when the incident was acknowledged,
+// resolved or escalated by someone else in the meantime.
+func (r *Repository) AdvanceIncidentEscalation(incidentID string, step int, next *time.Time) (bool, error) {
+	return r.advanceEscalation("incidents", incidentID, step, next)
+}
+
+// AdvanceGroupIncidentEscalation is AdvanceIncidentEscalation for group incidents
+func (r *Repository) AdvanceGroupIncidentEscalation(incidentID string, step int, next *time.Time) (bool, error) {
+	return r.advanceEscalation("monitor_group_incidents", incidentID, step, next)
+}
+
+// AddIncidentNotificationsSent counts notifications sent by an escalation step
+func (r *Repository) AddIncidentNotificationsSent(incidentID string, n int) error {
+	return r.addNotificationsSent("incidents", incidentID, n)
+}
+
+// AddGroupIncidentNotificationsSent counts notifications sent by an escalation step for a group incident
+func (r *Repository) AddGroupIncidentNotificationsSent(incidentID string, n int) error {
+	return r.addNotificationsSent("monitor_group_incidents", incidentID, n)
+}
+
+// StopIncidentEscalation cancels any further escalation steps
+func (r *Repository) StopIncidentEscalation(incidentID string) error {
+	return r.stopEscalation("incidents", incidentID)
+}
+
+// StopGroupIncidentEscalation cancels any further escalation steps of a group incident
+func (r *Repository) StopGroupIncidentEscalation(incidentID string) error {
+	return r.stopEscalation("monitor_group_incidents", incidentID)
+}
+
+func (r *Repository) startEscalation(table, incidentID, policyID string, firstStepAt time.Time) error {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			escalation_policy_id = $2,
+			escalation_step = 0,
+			next_escalation_at = $3
+		WHERE id = $1 AND acknowledged_at IS NULL AND resolved_at IS NULL`, table)
+
+	if _, err := r.db.Exec(query, incidentID, policyID, firstStepAt); err != nil {
+		return fmt.Errorf("failed to start escalation: %w", err)
+	}
+	return nil
+}
+
+func dueEscalationsQuery(table string) string {
+	return fmt.Sprintf(`
+		SELECT * FROM %s
+		WHERE next_escalation_at <= $1
+		AND escalation_policy_id IS NOT NULL
+		AND acknowledged_at IS NULL
+		AND resolved_at IS NULL
+		ORDER BY next_escalation_at
+		LIMIT $2`, table)
+}
+
+func (r *Repository) advanceEscalation(table, incidentID string, step int, next *time.Time) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE %s SET
+			escalation_step = $2 + 1,
+			next_escalation_at = $3
+		WHERE id = $1
+		AND escalation_step = $2
+		AND next_escalation_at IS NOT NULL
+		AND acknowledged_at IS NULL
+		AND resolved_at IS NULL`, table)
+
+	result, err := r.db.Exec(query, incidentID, step, next)
+	if err != nil {
+		return false, fmt.Errorf("failed to advance escalation: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	return rows == 1, nil
+}
+
+func (r *Repository) addNotificationsSent(table, incidentID string, n int) error {
+	query := fmt.Sprintf(`UPDATE %s SET notifications_sent = notifications_sent + $2 WHERE id = $1`, table)
+	if _, err := r.db.Exec(query, incidentID, n); err != nil {
+		return fmt.Errorf("failed to update notifications sent: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) stopEscalation(table, incidentID string) error {
+	query := fmt.Sprintf(`UPDATE %s SET next_escalation_at = NULL WHERE id = $1`, table)
+	if _, err := r.db.Exec(query, incidentID); err != nil {
+		return fmt.Errorf("failed to stop escalation: %w", err)
+	}
+	return nil
+}
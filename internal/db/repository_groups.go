@@ -430,12 +430,27 @@ func (r *Repository) GetActiveGroupIncident(groupID string) (*MonitorGroupIncide
 	return &incident, nil
 }
 
+func (r *Repository) GetGroupIncident(id, tenantID string) (*MonitorGroupIncident, error) {
+	var incident MonitorGroupIncident
+	query := `SELECT * FROM monitor_group_incidents WHERE id = $1 AND tenant_id = $2`
+
+	err := r.db.Get(&incident, query, id, tenantID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("group incident not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group incident: %w", err)
+	}
+	return &incident, nil
+}
+
+// UpdateGroupIncident saves the incident's state; notifications_sent is only
+// changed by AddGroupIncidentNotificationsSent
 func (r *Repository) UpdateGroupIncident(incident *MonitorGroupIncident) error {
 	query := `
 		UPDATE monitor_group_incidents SET
 			resolved_at = :resolved_at,
 			affected_monitors = :affected_monitors,
-			notifications_sent = :notifications_sent,
 			acknowledged_at = :acknowledged_at,
 			acknowledged_by = :acknowledged_by
 		WHERE id = :id`
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// GetMonitorByHeartbeatToken finds the heartbeat monitor a ping URL belongs to
+func (r *Repository) GetMonitorByHeartbeatToken(token string) (*Monitor, error) {
+	var m Monitor
+	query := `SELECT * FROM monitors WHERE heartbeat_token = $1 AND type = 'heartbeat'`
+	err := r.db.Get(&m, query, token)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("monitor not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitor by heartbeat token: %w", err)
+	}
+	return &m, nil
+}
+
+func (r *Repository) CreateHeartbeatPing(ping *HeartbeatPing) error {
+	query := `
+		INSERT INTO heartbeat_pings (
+			id, monitor_id, status, duration_ms, message, source_ip, received_at
+		) VALUES (
+			:id, :monitor_id, :status, :duration_ms, :message, :source_ip, :received_at
+		)`
+
+	if _, err := r.db.NamedExec(query, ping); err != nil {
+		return fmt.Errorf("failed to create heartbeat ping: %w", err)
+	}
+	return nil
+}
+
+// GetLatestHeartbeatPing returns the monitor's most recent ping, nil if it was never pinged
+func (r *Repository) GetLatestHeartbeatPing(monitorID string) (*HeartbeatPing, error) {
+	var ping HeartbeatPing
+	query := `
+		SELECT * FROM heartbeat_pings
+		WHERE monitor_id = $1
+		ORDER BY received_at DESC
+		LIMIT 1`
+
+	err := r.db.Get(&ping, query, monitorID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest heartbeat ping: %w", err)
+	}
+	return &ping, nil
+}
@@ -0,0 +1,427 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Notification outbox operations
+
+func (r *Repository) CreateNotificationDelivery(d *NotificationDelivery) error {
+	query := `
+		INSERT INTO notification_deliveries (
+			id, tenant_id, channel_type, channel, event, dedup_key,
+			monitor_id, group_id, incident_id, group_incident_id, batch_key,
+			payload, status, attempts, max_attempts, next_attempt_at,
+			created_at, updated_at
+		) VALUES (
+			:id, :tenant_id, :channel_type, :channel, :event, :dedup_key,
+			:monitor_id, :group_id, :incident_id, :group_incident_id, :batch_key,
+			:payload, :status, :attempts, :max_attempts, :next_attempt_at,
+			:created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExec(query, d)
+	if err != nil {
+		return fmt.Errorf("failed to create notification delivery: %w", err)
+	}
+	return nil
+}
+
+// ClaimDueNotificationDeliveries leases up to limit pending deliveries whose retry time has come.
+// The attempt counter is incremented and next_attempt_at is pushed to leaseUntil, so a delivery
+// claimed by a worker that dies before reporting back is picked up again once the lease expires.
+// A delivery is held back while an older one for the same dedup key and channel is still pending,
+// so e.g. a resolve never overtakes the trigger it belongs to.
+func (r *Repository) ClaimDueNotificationDeliveries(now, leaseUntil time.Time, limit int) ([]*NotificationDelivery, error) {
+	deliveries := []*NotificationDelivery{}
+	query := `
+		UPDATE notification_deliveries SET
+			attempts = attempts + 1,
+			next_attempt_at = $2,
+			updated_at = $1
+		WHERE id IN (
+			SELECT d.id FROM notification_deliveries d
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+			AND NOT EXISTS (
+				SELECT 1 FROM notification_deliveries earlier
+				WHERE earlier.status = 'pending'
+				AND earlier.dedup_key = d.dedup_key
+				AND earlier.channel = d.channel
+				AND earlier.created_at < d.created_at
+			)
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	if err := r.db.Select(&deliveries, query, now, leaseUntil, limit); err != nil {
+		return nil, fmt.Errorf("failed to claim notification deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// GetBatchDueTime returns when the open batch for batchKey will be sent, or nil when
+// there is none. Batches already picked up by a worker are not open anymore.
+func (r *Repository) GetBatchDueTime(batchKey string) (*time.Time, error) {
+	var due *time.Time
+	query := `
+		SELECT MIN(next_attempt_at) FROM notification_deliveries
+		WHERE batch_key = $1 AND status = 'pending' AND attempts = 0`
+
+	if err := r.db.Get(&due, query, batchKey); err != nil {
+		return nil, fmt.Errorf("failed to get batch due time: %w", err)
+	}
+	return due, nil
+}
+
+func (r *Repository) MarkNotificationDelivered(id string, deliveredAt time.Time) error {
+	query := `
+		UPDATE notification_deliveries SET
+			status = 'delivered',
+			delivered_at = $2,
+			last_error = NULL,
+			updated_at = $2
+		WHERE id = $1`
+
+	_, err := r.db.Exec(query, id, deliveredAt)
+	return err
+}
+
+// RescheduleNotificationDelivery records a failed attempt and when to try again
+func (r *Repository) RescheduleNotificationDelivery(id, lastError string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE notification_deliveries SET
+			last_error = $2,
+			next_attempt_at = $3,
+			updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := r.db.Exec(query, id, lastError, nextAttemptAt)
+	return err
+}
+
+// FailNotificationDelivery gives up on a delivery
+func (r *Repository) FailNotificationDelivery(id, lastError string) error {
+	query := `
+		UPDATE notification_deliveries SET
+			status = 'failed',
+			last_error = $2,
+			updated_at = NOW()
+		WHERE id = $1`
+
+	_, err := r.db.Exec(query, id, lastError)
+	return err
+}
+
+func (r *Repository) GetNotificationDeliveries(filters *DeliveryFilters) ([]*NotificationDelivery, error) {
+	deliveries := []*NotificationDelivery{}
+
+	where, args := deliveryFilterClause(filters)
+	query := "SELECT * FROM notification_deliveries" + where + " ORDER BY created_at DESC"
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	args = append(args, filters.Limit, filters.Offset)
+
+	err := r.db.Select(&deliveries, query, args...)
+	return deliveries, err
+}
+
+func (r *Repository) CountNotificationDeliveries(filters *DeliveryFilters) (int, error) {
+	var count int
+	where, args := deliveryFilterClause(filters)
+	err := r.db.Get(&count, "SELECT COUNT(*) FROM notification_deliveries"+where, args...)
+	return count, err
+}
+
+func deliveryFilterClause(filters *DeliveryFilters) (string, []interface{}) {
+	where := " WHERE tenant_id = $1"
+	args := []interface{}{filters.TenantID}
+
+	add := func(column, value string) {
+		if value == "" {
+			return
+		}
+		args = append(args, value)
+		where += fmt.Sprintf(" AND %s = $%d", column, len(args))
+	}
+	add("status", filters.Status)
+	add("channel_type", filters.ChannelType)
+	add("monitor_id", filters.MonitorID)
+	add("incident_id", filters.IncidentID)
+
+	return where, args
+}
+
+// GetIncidentByID loads an incident without tenant scoping, for background jobs
+func (r *Repository) GetIncidentByID(id string) (*Incident, error) {
+	var incident Incident
+	err := r.db.Get(&incident, `SELECT * FROM incidents WHERE id = $1`, id)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("incident not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incident: %w", err)
+	}
+	return &incident, nil
+}
+
+// MergeIncidentNotificationRefs adds refs to the incident without overwriting other columns
+func (r *Repository) MergeIncidentNotificationRefs(id string, refs JSONB) error {
+	query := `UPDATE incidents SET notification_refs = notification_refs || $2::jsonb WHERE id = $1`
+	_, err := r.db.Exec(query, id, refs)
+	return err
+}
+
+// GetAlertedChannels returns the distinct channels alerts for a monitor incident were queued for
+func (r *Repository) GetAlertedChannels(incidentID string) ([]NotificationChannel, error) {
+	return r.getAlertedChannels("incident_id", incidentID)
+}
+
+// GetGroupAlertedChannels returns the distinct channels alerts for a group incident were queued for
+func (r *Repository) GetGroupAlertedChannels(groupIncidentID string) ([]NotificationChannel, error) {
+	return r.getAlertedChannels("group_incident_id", groupIncidentID)
+}
+
+func (r *Repository) getAlertedChannels(column, id string) ([]NotificationChannel, error) {
+	snapshots := []ChannelSnapshot{}
+	query := fmt.Sprintf(`
+		SELECT DISTINCT channel FROM notification_deliveries
+		WHERE %s = $1
+		AND event IN ('down', 'degraded', 'reminder')
+		AND status <> 'failed'`, column)
+
+	if err := r.db.Select(&snapshots, query, id); err != nil {
+		return nil, fmt.Errorf("failed to get alerted channels: %w", err)
+	}
+
+	channels := make([]NotificationChannel, len(snapshots))
+	for i, snapshot := range snapshots {
+		channels[i] = NotificationChannel(snapshot)
+	}
+	return channels, nil
+}
+
+// Notification channel operations
+
+func (r *Repository) CreateNotificationChannel(ch *NotificationChannel) error {
+	query := `
+		INSERT INTO notification_channels (
+			id, tenant_id, name, type, config, enabled,
+			aggregation_window, digest, min_severity, schedule,
+			created_at, updated_at
+		) VALUES (
+			:id, :tenant_id, :name, :type, :config, :enabled,
+			:aggregation_window, :digest, :min_severity, :schedule,
+			:created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExec(query, ch)
+	if err != nil {
+		return fmt.Errorf("failed to create notification channel: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetNotificationChannel(id, tenantID string) (*NotificationChannel, error) {
+	var ch NotificationChannel
+	query := `SELECT * FROM notification_channels WHERE id = $1 AND tenant_id = $2`
+	err := r.db.Get(&ch, query, id, tenantID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("notification channel not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification channel: %w", err)
+	}
+	return &ch, nil
+}
+
+func (r *Repository) GetNotificationChannelsByTenant(tenantID string) ([]*NotificationChannel, error) {
+	channels := []*NotificationChannel{}
+	query := `SELECT * FROM notification_channels WHERE tenant_id = $1 ORDER BY name`
+	err := r.db.Select(&channels, query, tenantID)
+	return channels, err
+}
+
+// GetNotificationChannelsByIDs loads the tenant's channels with the given IDs, keyed by ID
+func (r *Repository) GetNotificationChannelsByIDs(tenantID string, ids []string) (map[string]*NotificationChannel, error) {
+	result := make(map[string]*NotificationChannel)
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	channels := []*NotificationChannel{}
+	query := `SELECT * FROM notification_channels WHERE tenant_id = $1 AND id::text = ANY($2)`
+	if err := r.db.Select(&channels, query, tenantID, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("failed to get notification channels: %w", err)
+	}
+	for _, ch := range channels {
+		result[ch.ID] = ch
+	}
+	return result, nil
+}
+
+func (r *Repository) UpdateNotificationChannel(ch *NotificationChannel) error {
+	query := `
+		UPDATE notification_channels SET
+			name = :name,
+			type = :type,
+			config = :config,
+			enabled = :enabled,
+			aggregation_window = :aggregation_window,
+			digest = :digest,
+			min_severity = :min_severity,
+			schedule = :schedule,
+			updated_at = :updated_at
+		WHERE id = :id AND tenant_id = :tenant_id`
+
+	_, err := r.db.NamedExec(query, ch)
+	if err != nil {
+		return fmt.Errorf("failed to update notification channel: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteNotificationChannel(id, tenantID string) error {
+	result, err := r.db.Exec(`DELETE FROM notification_channels WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("notification channel not found")
+	}
+	return nil
+}
+
+// CountNotificationChannelReferences counts monitors, groups, group alert rules,
+// escalation policies and on-call members referencing the channel
+func (r *Repository) CountNotificationChannelReferences(id, tenantID string) (int, error) {
+	var count int
+	ref, err := json.Marshal([]map[string]string{{"id": id}})
+	if err != nil {
+		return 0, err
+	}
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM monitors
+				WHERE tenant_id = $1 AND notification_config->'channels' @> $2::jsonb)
+			+ (SELECT COUNT(*) FROM monitor_groups
+				WHERE tenant_id = $1 AND notification_config->'channels' @> $2::jsonb)
+			+ (SELECT COUNT(*) FROM monitor_group_alert_rules r
+				JOIN monitor_groups g ON g.id = r.group_id
+				WHERE g.tenant_id = $1 AND r.notification_channels @> $2::jsonb)
+			+ (SELECT COUNT(*) FROM escalation_policies
+				WHERE tenant_id = $1 AND steps @> jsonb_build_array(jsonb_build_object('channels', $2::jsonb)))
+			+ (SELECT COUNT(*) FROM oncall_schedules
+				WHERE tenant_id = $1 AND members @> jsonb_build_array(jsonb_build_object('channels', $2::jsonb)))`
+
+	err = r.db.Get(&count, query, tenantID, string(ref))
+	return count, err
+}
+
+// Notification template operations
+
+func (r *Repository) CreateNotificationTemplate(t *NotificationTemplate) error {
+	query := `
+		INSERT INTO notification_templates (
+			id, tenant_id, channel_id, event, title, body, created_at, updated_at
+		) VALUES (
+			:id, :tenant_id, :channel_id, :event, :title, :body, :created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExec(query, t)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("notification template already exists")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create notification template: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetNotificationTemplate(id, tenantID string) (*NotificationTemplate, error) {
+	var t NotificationTemplate
+	query := `SELECT * FROM notification_templates WHERE id = $1 AND tenant_id = $2`
+	err := r.db.Get(&t, query, id, tenantID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("notification template not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification template: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *Repository) GetNotificationTemplatesByTenant(tenantID string) ([]*NotificationTemplate, error) {
+	templates := []*NotificationTemplate{}
+	query := `
+		SELECT * FROM notification_templates
+		WHERE tenant_id = $1
+		ORDER BY channel_id NULLS FIRST, event NULLS FIRST`
+	err := r.db.Select(&templates, query, tenantID)
+	return templates, err
+}
+
+// FindNotificationTemplate returns the most specific template for a channel and event:
+// channel and event, then channel only, then tenant and event, then tenant only
+func (r *Repository) FindNotificationTemplate(tenantID, channelID, event string) (*NotificationTemplate, error) {
+	var t NotificationTemplate
+	query := `
+		SELECT * FROM notification_templates
+		WHERE tenant_id = $1
+		AND (channel_id IS NULL OR channel_id::text = $2)
+		AND (event IS NULL OR event = $3)
+		ORDER BY channel_id IS NULL, event IS NULL
+		LIMIT 1`
+	err := r.db.Get(&t, query, tenantID, channelID, event)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no notification template")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification template: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *Repository) UpdateNotificationTemplate(t *NotificationTemplate) error {
+	query := `
+		UPDATE notification_templates SET
+			channel_id = :channel_id,
+			event = :event,
+			title = :title,
+			body = :body,
+			updated_at = :updated_at
+		WHERE id = :id AND tenant_id = :tenant_id`
+
+	_, err := r.db.NamedExec(query, t)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("notification template already exists")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update notification template: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteNotificationTemplate(id, tenantID string) error {
+	result, err := r.db.Exec(`DELETE FROM notification_templates WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification template: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("notification template not found")
+	}
+	return nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
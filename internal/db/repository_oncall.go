@@ -0,0 +1,150 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// On-call schedule operations
+
+func (r *Repository) CreateOnCallSchedule(s *OnCallSchedule) error {
+	query := `
+		INSERT INTO oncall_schedules (
+			id, tenant_id, name, description, timezone, members, layers,
+			created_at, updated_at
+		) VALUES (
+			:id, :tenant_id, :name, :description, :timezone, :members, :layers,
+			:created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExec(query, s)
+	if err != nil {
+		return fmt.Errorf("failed to create on-call schedule: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetOnCallSchedule(id, tenantID string) (*OnCallSchedule, error) {
+	var s OnCallSchedule
+	query := `SELECT * FROM oncall_schedules WHERE id = $1 AND tenant_id = $2`
+	err := r.db.Get(&s, query, id, tenantID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("on-call schedule not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get on-call schedule: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *Repository) GetOnCallSchedulesByTenant(tenantID string) ([]*OnCallSchedule, error) {
+	schedules := []*OnCallSchedule{}
+	query := `SELECT * FROM oncall_schedules WHERE tenant_id = $1 ORDER BY name`
+	err := r.db.Select(&schedules, query, tenantID)
+	return schedules, err
+}
+
+func (r *Repository) UpdateOnCallSchedule(s *OnCallSchedule) error {
+	query := `
+		UPDATE oncall_schedules SET
+			name = :name,
+			description = :description,
+			timezone = :timezone,
+			members = :members,
+			layers = :layers,
+			updated_at = :updated_at
+		WHERE id = :id AND tenant_id = :tenant_id`
+
+	_, err := r.db.NamedExec(query, s)
+	if err != nil {
+		return fmt.Errorf("failed to update on-call schedule: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteOnCallSchedule(id, tenantID string) error {
+	result, err := r.db.Exec(`DELETE FROM oncall_schedules WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete on-call schedule: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("on-call schedule not found")
+	}
+	return nil
+}
+
+// CountOnCallScheduleReferences counts stored channels, monitors, groups, group alert
+// rules and escalation policies that page the schedule
+func (r *Repository) CountOnCallScheduleReferences(id, tenantID string) (int, error) {
+	var count int
+	target, err := json.Marshal([]map[string]interface{}{{
+		"type":   "oncall",
+		"config": map[string]string{"schedule_id": id},
+	}})
+	if err != nil {
+		return 0, err
+	}
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM notification_channels
+				WHERE tenant_id = $1 AND type = 'oncall' AND config->>'schedule_id' = $3)
+			+ (SELECT COUNT(*) FROM monitors
+				WHERE tenant_id = $1 AND notification_config->'channels' @> $2::jsonb)
+			+ (SELECT COUNT(*) FROM monitor_groups
+				WHERE tenant_id = $1 AND notification_config->'channels' @> $2::jsonb)
+			+ (SELECT COUNT(*) FROM monitor_group_alert_rules r
+				JOIN monitor_groups g ON g.id = r.group_id
+				WHERE g.tenant_id = $1 AND r.notification_channels @> $2::jsonb)
+			+ (SELECT COUNT(*) FROM escalation_policies
+				WHERE tenant_id = $1 AND steps @> jsonb_build_array(jsonb_build_object('channels', $2::jsonb)))`
+
+	err = r.db.Get(&count, query, tenantID, string(target), id)
+	return count, err
+}
+
+// On-call override operations
+
+func (r *Repository) CreateOnCallOverride(o *OnCallOverride) error {
+	query := `
+		INSERT INTO oncall_overrides (
+			id, schedule_id, user_id, starts_at, ends_at, created_by, created_at
+		) VALUES (
+			:id, :schedule_id, :user_id, :starts_at, :ends_at, :created_by, :created_at
+		)`
+
+	_, err := r.db.NamedExec(query, o)
+	if err != nil {
+		return fmt.Errorf("failed to create on-call override: %w", err)
+	}
+	return nil
+}
+
+// GetOnCallOverrides returns the schedule's overrides overlapping [from, to).
+// Overrides are stored in UTC.
+func (r *Repository) GetOnCallOverrides(scheduleID string, from, to time.Time) ([]*OnCallOverride, error) {
+	overrides := []*OnCallOverride{}
+	query := `
+		SELECT * FROM oncall_overrides
+		WHERE schedule_id = $1 AND ends_at > $2 AND starts_at <= $3
+		ORDER BY starts_at`
+
+	if err := r.db.Select(&overrides, query, scheduleID, from.UTC(), to.UTC()); err != nil {
+		return nil, fmt.Errorf("failed to get on-call overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+func (r *Repository) DeleteOnCallOverride(id, scheduleID string) error {
+	result, err := r.db.Exec(`DELETE FROM oncall_overrides WHERE id = $1 AND schedule_id = $2`, id, scheduleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete on-call override: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("on-call override not found")
+	}
+	return nil
+}
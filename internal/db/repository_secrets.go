@@ -0,0 +1,117 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	"github.com/lib/pq"
+)
+
+// Secret operations
+
+func (r *Repository) CreateSecret(s *Secret) error {
+	query := `
+		INSERT INTO secrets (
+			id, tenant_id, name, description, encrypted_key, encrypted_value,
+			created_by, created_at, updated_at
+		) VALUES (
+			:id, :tenant_id, :name, :description, :encrypted_key, :encrypted_value,
+			:created_by, :created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExec(query, s)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("secret already exists")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create secret: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) GetSecret(id, tenantID string) (*Secret, error) {
+	var s Secret
+	query := `SELECT * FROM secrets WHERE id = $1 AND tenant_id = $2`
+	err := r.db.Get(&s, query, id, tenantID)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("secret not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *Repository) GetSecretsByTenant(tenantID string) ([]*Secret, error) {
+	secrets := []*Secret{}
+	query := `SELECT * FROM secrets WHERE tenant_id = $1 ORDER BY name`
+	err := r.db.Select(&secrets, query, tenantID)
+	return secrets, err
+}
+
+// GetSecretsByNames returns the tenant's secrets with the given names; names
+// without a secret are left out
+func (r *Repository) GetSecretsByNames(tenantID string, names []string) ([]*Secret, error) {
+	secrets := []*Secret{}
+	query := `SELECT * FROM secrets WHERE tenant_id = $1 AND name = ANY($2)`
+	if err := r.db.Select(&secrets, query, tenantID, pq.Array(names)); err != nil {
+		return nil, fmt.Errorf("failed to get secrets: %w", err)
+	}
+	return secrets, nil
+}
+
+func (r *Repository) UpdateSecret(s *Secret) error {
+	query := `
+		UPDATE secrets SET
+			description = :description,
+			encrypted_key = :encrypted_key,
+			encrypted_value = :encrypted_value,
+			updated_at = :updated_at
+		WHERE id = :id AND tenant_id = :tenant_id`
+
+	_, err := r.db.NamedExec(query, s)
+	if err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteSecret(id, tenantID string) error {
+	result, err := r.db.Exec(`DELETE FROM secrets WHERE id = $1 AND tenant_id = $2`, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to delete secret: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("secret not found")
+	}
+	return nil
+}
+
+// CountSecretReferences counts monitors, groups, channels, group alert rules,
+// escalation policies and on-call schedules whose config references the secret
+func (r *Repository) CountSecretReferences(name, tenantID string) (int, error) {
+	// Quotes are escaped in the JSON text the pattern is matched against
+	pattern := `\{\{\s*secret\s+\\"` + regexp.QuoteMeta(name) + `\\"\s*\}\}`
+
+	var count int
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM monitors
+				WHERE tenant_id = $1 AND (to_jsonb(target)::text ~ $2 OR config::text ~ $2 OR notification_config::text ~ $2))
+			+ (SELECT COUNT(*) FROM monitor_groups
+				WHERE tenant_id = $1 AND notification_config::text ~ $2)
+			+ (SELECT COUNT(*) FROM notification_channels
+				WHERE tenant_id = $1 AND config::text ~ $2)
+			+ (SELECT COUNT(*) FROM monitor_group_alert_rules r
+				JOIN monitor_groups g ON g.id = r.group_id
+				WHERE g.tenant_id = $1 AND r.notification_channels::text ~ $2)
+			+ (SELECT COUNT(*) FROM escalation_policies
+				WHERE tenant_id = $1 AND steps::text ~ $2)
+			+ (SELECT COUNT(*) FROM oncall_schedules
+				WHERE tenant_id = $1 AND members::text ~ $2)`
+
+	err := r.db.Get(&count, query, tenantID, pattern)
+	return count, err
+}
@@ -0,0 +1,238 @@
+package escalation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leozw/uptime-guardian/internal/config"
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/notifications"
+	"go.uber.org/zap"
+)
+
+// Service walks unacknowledged incidents through their escalation policy. Each
+// step is claimed with a conditional update before it is sent, so an incident
+// acknowledged or resolved in the meantime is never escalated and several
+// workers can run the loop side by side.
+type Service struct {
+	repo   *db.Repository
+	outbox *notifications.Outbox
+	logger *zap.Logger
+	cfg    config.EscalationConfig
+}
+
+func NewService(repo *db.Repository, outbox *notifications.Outbox, logger *zap.Logger, cfg config.EscalationConfig) *Service {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 15 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	return &Service{
+		repo:   repo,
+		outbox: outbox,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// Start escalates due incidents until ctx is cancelled
+func (s *Service) Start(ctx context.Context) {
+	s.logger.Info("Escalation loop started", zap.Duration("poll_interval", s.cfg.PollInterval))
+
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Escalation loop stopped")
+			return
+		case <-ticker.C:
+			s.processDue()
+		}
+	}
+}
+
+func (s *Service) processDue() {
+	now := time.Now()
+
+	incidents, err := s.repo.GetDueIncidentEscalations(now, s.cfg.BatchSize)
+	if err != nil {
+		s.logger.Error("Failed to get due escalations", zap.Error(err))
+	}
+	for _, incident := range incidents {
+		s.escalateIncident(incident, now)
+	}
+
+	groupIncidents, err := s.repo.GetDueGroupIncidentEscalations(now, s.cfg.BatchSize)
+	if err != nil {
+		s.logger.Error("Failed to get due group escalations", zap.Error(err))
+	}
+	for _, incident := range groupIncidents {
+		s.escalateGroupIncident(incident, now)
+	}
+}
+
+func (s *Service) escalateIncident(incident *db.Incident, now time.Time) {
+	logger := s.logger.With(zap.String("incident_id", incident.ID))
+
+	policy, done, err := s.policy(incident.TenantID, *incident.EscalationPolicyID, incident.EscalationStep)
+	if err != nil {
+		// Retried on the next poll
+		logger.Error("Failed to get escalation policy", zap.Error(err))
+		return
+	}
+	if done {
+		if err := s.repo.StopIncidentEscalation(incident.ID); err != nil {
+			logger.Error("Failed to stop escalation", zap.Error(err))
+		}
+		return
+	}
+
+	monitor, err := s.repo.GetMonitorByID(incident.MonitorID)
+	if err != nil {
+		logger.Error("Failed to get monitor for escalation", zap.Error(err))
+		return
+	}
+	// The latest failing check, if the monitor has not just come back up
+	var result *db.CheckResult
+	if results, err := s.repo.GetCheckHistory(monitor.ID, monitor.TenantID, 1); err == nil && len(results) > 0 && results[0].Status != db.StatusUp {
+		result = results[0]
+	}
+
+	step := incident.EscalationStep
+	claimed, err := s.repo.AdvanceIncidentEscalation(incident.ID, step, nextStepAt(policy, step, now))
+	if err != nil {
+		logger.Error("Failed to advance escalation", zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+	incident.EscalationStep = step + 1
+
+	event := notifications.EventDown
+	if incident.Severity == "warning" {
+		event = notifications.EventDegraded
+	}
+	msg := &notifications.Message{
+		Event:      event,
+		Monitor:    monitor,
+		Result:     result,
+		Incident:   incident,
+		Escalation: step + 1,
+	}
+
+	queued := s.outbox.Enqueue(policy.Steps[step].Channels, msg)
+	if err := s.repo.AddIncidentNotificationsSent(incident.ID, queued); err != nil {
+		logger.Error("Failed to update incident notification count", zap.Error(err))
+	}
+
+	timelineEvent := &db.IncidentEvent{
+		ID:          uuid.New().String(),
+		IncidentID:  incident.ID,
+		EventType:   db.IncidentEventEscalated,
+		EventTime:   now,
+		Description: fmt.Sprintf("Escalated to level %d of policy %s", step+1, policy.Name),
+		Metadata: map[string]interface{}{
+			"escalation_policy_id": policy.ID,
+			"level":                step + 1,
+			"channels":             queued,
+		},
+	}
+	if err := s.repo.CreateIncidentEvent(timelineEvent); err != nil {
+		logger.Error("Failed to create escalation event", zap.Error(err))
+	}
+
+	logger.Info("Escalated incident",
+		zap.String("monitor_id", monitor.ID),
+		zap.String("escalation_policy_id", policy.ID),
+		zap.Int("level", step+1),
+		zap.Int("channels", queued),
+	)
+}
+
+func (s *Service) escalateGroupIncident(incident *db.MonitorGroupIncident, now time.Time) {
+	logger := s.logger.With(zap.String("group_incident_id", incident.ID))
+
+	policy, done, err := s.policy(incident.TenantID, *incident.EscalationPolicyID, incident.EscalationStep)
+	if err != nil {
+		// Retried on the next poll
+		logger.Error("Failed to get escalation policy", zap.Error(err))
+		return
+	}
+	if done {
+		if err := s.repo.StopGroupIncidentEscalation(incident.ID); err != nil {
+			logger.Error("Failed to stop escalation", zap.Error(err))
+		}
+		return
+	}
+
+	group, err := s.repo.GetMonitorGroup(incident.GroupID, incident.TenantID)
+	if err != nil {
+		logger.Error("Failed to get monitor group for escalation", zap.Error(err))
+		return
+	}
+	// Without a status the message falls back to a plain "unhealthy"
+	status, _ := s.repo.GetGroupStatus(group.ID)
+
+	step := incident.EscalationStep
+	claimed, err := s.repo.AdvanceGroupIncidentEscalation(incident.ID, step, nextStepAt(policy, step, now))
+	if err != nil {
+		logger.Error("Failed to advance escalation", zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+	incident.EscalationStep = step + 1
+
+	event := notifications.EventDown
+	if status != nil {
+		event = notifications.EventForStatus(status.OverallStatus)
+	}
+	msg := &notifications.Message{
+		Event:      event,
+		Group:      group,
+		GroupInc:   incident,
+		GroupStat:  status,
+		Escalation: step + 1,
+	}
+
+	queued := s.outbox.Enqueue(policy.Steps[step].Channels, msg)
+	if err := s.repo.AddGroupIncidentNotificationsSent(incident.ID, queued); err != nil {
+		logger.Error("Failed to update group incident notification count", zap.Error(err))
+	}
+
+	logger.Info("Escalated group incident",
+		zap.String("group_id", group.ID),
+		zap.String("escalation_policy_id", policy.ID),
+		zap.Int("level", step+1),
+		zap.Int("channels", queued),
+	)
+}
+
+// policy loads the incident's policy. done is true when the escalation has
+// nothing left to do: the policy was deleted or step is past its last step.
+func (s *Service) policy(tenantID, policyID string, step int) (policy *db.EscalationPolicy, done bool, err error) {
+	policy, err = s.repo.GetEscalationPolicy(policyID, tenantID)
+	if err != nil {
+		if err.Error() == "escalation policy not found" {
+			return nil, true, nil
+		}
+		return nil, false, err
+	}
+	return policy, step >= len(policy.Steps), nil
+}
+
+// nextStepAt returns when the step after step is due, nil after the last step
+func nextStepAt(policy *db.EscalationPolicy, step int, now time.Time) *time.Time {
+	if step+1 >= len(policy.Steps) {
+		return nil
+	}
+	next := now.Add(policy.Delay(step + 1))
+	return &next
+}
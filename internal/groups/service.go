@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/leozw/uptime-guardian/internal/db"
 	"github.com/leozw/uptime-guardian/internal/metrics"
+	"github.com/leozw/uptime-guardian/internal/notifications"
 	"go.uber.org/zap"
 )
 
@@ -14,13 +15,15 @@ type Service struct {
 	repo    *db.Repository
 	logger  *zap.Logger
 	metrics *metrics.Collector
+	outbox  *notifications.Outbox
 }
 
-func NewService(repo *db.Repository, logger *zap.Logger, metrics *metrics.Collector) *Service {
+func NewService(repo *db.Repository, logger *zap.Logger, metrics *metrics.Collector, outbox *notifications.Outbox) *Service {
 	return &Service{
 		repo:    repo,
 		logger:  logger,
 		metrics: metrics,
+		outbox:  outbox,
 	}
 }
 
@@ -253,6 +256,7 @@ func (s *Service) checkGroupIncidents(group *db.MonitorGroup, status *db.Monitor
 		if err := s.repo.UpdateGroupIncident(activeIncident); err != nil {
 			return fmt.Errorf("failed to resolve incident: %w", err)
 		}
+		s.stopEscalation(activeIncident)
 
 		// Record metrics
 		s.metrics.RecordGroupIncidentResolved(activeIncident, group)
@@ -261,6 +265,8 @@ func (s *Service) checkGroupIncidents(group *db.MonitorGroup, status *db.Monitor
 			zap.String("incident_id", activeIncident.ID),
 			zap.String("group_id", group.ID),
 		)
+
+		s.sendGroupRecovery(group, activeIncident, status)
 	}
 
 	return nil
@@ -309,32 +315,136 @@ func (s *Service) sendGroupNotifications(group *db.MonitorGroup, incident *db.Mo
 		channels = group.NotificationConf.Channels
 	}
 
-	for _, channel := range channels {
-		if channel.Enabled {
-			// TODO: Implement actual notification sending
-			s.logger.Info("Sending group notification",
-				zap.String("channel_type", channel.Type),
-				zap.String("group_id", group.ID),
-				zap.String("group_name", group.Name),
-				zap.String("incident_id", incident.ID),
-				zap.Float64("health_score", status.HealthScore),
-				zap.String("message", status.Message),
-			)
-
-			// Record notification metrics
-			s.metrics.RecordNotificationSent(
-				group.TenantID,
-				group.ID,
-				channel.Type,
-				true, // Simulate success
-				0.1,  // Simulated latency
-			)
-		}
+	msg := &notifications.Message{
+		Event:     notifications.EventForStatus(status.OverallStatus),
+		Group:     group,
+		GroupRule: rule,
+		GroupInc:  incident,
+		GroupStat: status,
 	}
 
 	// Update incident notification count
-	incident.NotificationsSent++
-	s.repo.UpdateGroupIncident(incident)
+	queued := s.outbox.Enqueue(channels, msg)
+	incident.NotificationsSent += queued
+	if err := s.repo.AddGroupIncidentNotificationsSent(incident.ID, queued); err != nil {
+		s.logger.Error("Failed to update group incident notification count", zap.Error(err))
+	}
+
+	s.startEscalation(group, incident)
+}
+
+// startEscalation attaches the group's escalation policy to a group incident that
+// has just alerted
+func (s *Service) startEscalation(group *db.MonitorGroup, incident *db.MonitorGroupIncident) {
+	policyID := group.NotificationConf.EscalationPolicyID
+	if policyID == "" || incident.EscalationPolicyID != nil {
+		return
+	}
+
+	policy, err := s.repo.GetEscalationPolicy(policyID, group.TenantID)
+	if err != nil {
+		s.logger.Error("Failed to get escalation policy",
+			zap.Error(err),
+			zap.String("group_id", group.ID),
+			zap.String("escalation_policy_id", policyID),
+		)
+		return
+	}
+	if len(policy.Steps) == 0 {
+		return
+	}
+
+	firstStepAt := time.Now().Add(policy.Delay(0))
+	if err := s.repo.StartGroupIncidentEscalation(incident.ID, policy.ID, firstStepAt); err != nil {
+		s.logger.Error("Failed to start escalation", zap.Error(err), zap.String("incident_id", incident.ID))
+		return
+	}
+	incident.EscalationPolicyID = &policy.ID
+	incident.NextEscalationAt = &firstStepAt
+}
+
+func (s *Service) stopEscalation(incident *db.MonitorGroupIncident) {
+	if incident.NextEscalationAt == nil {
+		return
+	}
+	if err := s.repo.StopGroupIncidentEscalation(incident.ID); err != nil {
+		s.logger.Error("Failed to stop escalation", zap.Error(err), zap.String("incident_id", incident.ID))
+		return
+	}
+	incident.NextEscalationAt = nil
+}
+
+// AcknowledgeGroupIncident marks a group incident as acknowledged, which stops its
+// escalation. Integrations mirroring the incident are told.
+func (s *Service) AcknowledgeGroupIncident(groupID, incidentID, tenantID, userEmail string) error {
+	incident, err := s.repo.GetGroupIncident(incidentID, tenantID)
+	if err != nil {
+		return err
+	}
+	if incident.GroupID != groupID {
+		return fmt.Errorf("group incident not found")
+	}
+	if incident.AcknowledgedAt != nil {
+		return fmt.Errorf("incident already acknowledged")
+	}
+
+	now := time.Now()
+	incident.AcknowledgedAt = &now
+	incident.AcknowledgedBy = &userEmail
+
+	if err := s.repo.UpdateGroupIncident(incident); err != nil {
+		return fmt.Errorf("failed to acknowledge group incident: %w", err)
+	}
+	s.stopEscalation(incident)
+
+	if incident.NotificationsSent == 0 {
+		return nil
+	}
+	group, err := s.repo.GetMonitorGroup(groupID, tenantID)
+	if err != nil {
+		s.logger.Error("Failed to get monitor group", zap.Error(err))
+		return nil
+	}
+	channels, err := s.repo.GetGroupAlertedChannels(incident.ID)
+	if err != nil {
+		s.logger.Error("Failed to get alerted channels", zap.Error(err), zap.String("incident_id", incident.ID))
+		return nil
+	}
+
+	msg := &notifications.Message{
+		Event:    notifications.EventAcknowledged,
+		Group:    group,
+		GroupInc: incident,
+	}
+	s.outbox.EnqueueLifecycle(channels, msg)
+	return nil
+}
+
+// sendGroupRecovery notifies the channels that were alerted that the group is healthy again.
+// Integrations mirroring the incident are always told so their alert gets closed.
+func (s *Service) sendGroupRecovery(group *db.MonitorGroup, incident *db.MonitorGroupIncident, status *db.MonitorGroupStatus) {
+	if incident.NotificationsSent == 0 {
+		return
+	}
+
+	channels, err := s.repo.GetGroupAlertedChannels(incident.ID)
+	if err != nil {
+		s.logger.Error("Failed to get alerted channels", zap.Error(err), zap.String("incident_id", incident.ID))
+		return
+	}
+
+	msg := &notifications.Message{
+		Event:     notifications.EventRecovery,
+		Group:     group,
+		GroupInc:  incident,
+		GroupStat: status,
+	}
+
+	if group.NotificationConf.OnRecovery {
+		s.outbox.Enqueue(channels, msg)
+	} else {
+		s.outbox.EnqueueLifecycle(channels, msg)
+	}
 }
 
 // UpdateAllGroupStatuses updates status for all groups in a tenant
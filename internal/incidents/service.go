@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/leozw/uptime-guardian/internal/db"
 	"github.com/leozw/uptime-guardian/internal/metrics"
+	"github.com/leozw/uptime-guardian/internal/notifications"
 	"go.uber.org/zap"
 )
 
@@ -14,13 +15,15 @@ type Service struct {
 	repo    *db.Repository
 	logger  *zap.Logger
 	metrics *metrics.Collector
+	outbox  *notifications.Outbox
 }
 
-func NewService(repo *db.Repository, logger *zap.Logger, metrics *metrics.Collector) *Service {
+func NewService(repo *db.Repository, logger *zap.Logger, metrics *metrics.Collector, outbox *notifications.Outbox) *Service {
 	return &Service{
 		repo:    repo,
 		logger:  logger,
 		metrics: metrics,
+		outbox:  outbox,
 	}
 }
 
@@ -44,6 +47,7 @@ func (s *Service) CreateOrUpdateIncident(monitor *db.Monitor, result *db.CheckRe
 				DowntimeMinutes:   0,
 				AffectedChecks:    1,
 				NotificationsSent: 0,
+				NotificationRefs:  make(db.JSONB),
 			}
 
 			if err := s.repo.CreateIncident(incident); err != nil {
@@ -93,6 +97,7 @@ func (s *Service) CreateOrUpdateIncident(monitor *db.Monitor, result *db.CheckRe
 		if err := s.repo.UpdateIncident(activeIncident); err != nil {
 			return fmt.Errorf("failed to resolve incident: %w", err)
 		}
+		s.stopEscalation(activeIncident)
 
 		// Criar evento de resolução
 		event := &db.IncidentEvent{
@@ -119,6 +124,8 @@ func (s *Service) CreateOrUpdateIncident(monitor *db.Monitor, result *db.CheckRe
 			zap.String("monitor_id", monitor.ID),
 			zap.Int("downtime_minutes", activeIncident.DowntimeMinutes),
 		)
+
+		s.notifyRecovery(monitor, activeIncident, result)
 	}
 
 	return nil
@@ -159,6 +166,7 @@ func (s *Service) AcknowledgeIncident(incidentID, tenantID, userEmail string) er
 	if err := s.repo.UpdateIncident(incident); err != nil {
 		return fmt.Errorf("failed to acknowledge incident: %w", err)
 	}
+	s.stopEscalation(incident)
 
 	// Criar evento de acknowledgment
 	event := &db.IncidentEvent{
@@ -177,11 +185,111 @@ func (s *Service) AcknowledgeIncident(incidentID, tenantID, userEmail string) er
 	// Record acknowledgment metrics
 	if monitor != nil {
 		s.metrics.RecordIncidentAcknowledged(incident, monitor)
+		s.notifyLifecycle(monitor, incident, nil, notifications.EventAcknowledged)
 	}
 
 	return nil
 }
 
+// StartEscalation attaches the monitor's escalation policy to an incident that has
+// just sent its first alert. The escalation loop notifies the policy's steps from
+// then on, until the incident is acknowledged or resolved.
+func (s *Service) StartEscalation(monitor *db.Monitor, incident *db.Incident) {
+	policyID := monitor.NotificationConf.EscalationPolicyID
+	if policyID == "" || incident.EscalationPolicyID != nil || incident.AcknowledgedAt != nil {
+		return
+	}
+
+	policy, err := s.repo.GetEscalationPolicy(policyID, monitor.TenantID)
+	if err != nil {
+		s.logger.Error("Failed to get escalation policy",
+			zap.Error(err),
+			zap.String("monitor_id", monitor.ID),
+			zap.String("escalation_policy_id", policyID),
+		)
+		return
+	}
+	if len(policy.Steps) == 0 {
+		return
+	}
+
+	firstStepAt := time.Now().Add(policy.Delay(0))
+	if err := s.repo.StartIncidentEscalation(incident.ID, policy.ID, firstStepAt); err != nil {
+		s.logger.Error("Failed to start escalation", zap.Error(err), zap.String("incident_id", incident.ID))
+		return
+	}
+	incident.EscalationPolicyID = &policy.ID
+	incident.NextEscalationAt = &firstStepAt
+}
+
+func (s *Service) stopEscalation(incident *db.Incident) {
+	if incident.NextEscalationAt == nil {
+		return
+	}
+	if err := s.repo.StopIncidentEscalation(incident.ID); err != nil {
+		s.logger.Error("Failed to stop escalation", zap.Error(err), zap.String("incident_id", incident.ID))
+		return
+	}
+	incident.NextEscalationAt = nil
+}
+
+// notifyRecovery sends the "all clear" to the channels that were alerted when
+// OnRecovery is enabled. Integrations that mirror the incident (PagerDuty, Opsgenie)
+// are always told, otherwise their alert would stay open.
+func (s *Service) notifyRecovery(monitor *db.Monitor, incident *db.Incident, result *db.CheckResult) {
+	if s.outbox == nil || incident.NotificationsSent == 0 {
+		return
+	}
+
+	msg := &notifications.Message{
+		Event:    notifications.EventRecovery,
+		Monitor:  monitor,
+		Result:   result,
+		Incident: incident,
+	}
+
+	channels := s.alertedChannels(monitor, incident)
+	var queued int
+	if monitor.NotificationConf.OnRecovery {
+		queued = s.outbox.Enqueue(channels, msg)
+	} else {
+		queued = s.outbox.EnqueueLifecycle(channels, msg)
+	}
+
+	s.logger.Info("Queued recovery notifications",
+		zap.String("incident_id", incident.ID),
+		zap.String("monitor_id", monitor.ID),
+		zap.Int("channels", queued),
+	)
+}
+
+// notifyLifecycle propagates an incident event to integrations that mirror the incident.
+// Nothing is sent when the incident never triggered an alert.
+func (s *Service) notifyLifecycle(monitor *db.Monitor, incident *db.Incident, result *db.CheckResult, event notifications.EventType) {
+	if s.outbox == nil || incident.NotificationsSent == 0 {
+		return
+	}
+
+	msg := &notifications.Message{
+		Event:    event,
+		Monitor:  monitor,
+		Result:   result,
+		Incident: incident,
+	}
+	s.outbox.EnqueueLifecycle(s.alertedChannels(monitor, incident), msg)
+}
+
+// alertedChannels returns the channels the incident's alerts went to, falling back
+// to the monitor's current channels when the delivery log cannot be read
+func (s *Service) alertedChannels(monitor *db.Monitor, incident *db.Incident) []db.NotificationChannel {
+	channels, err := s.repo.GetAlertedChannels(incident.ID)
+	if err != nil {
+		s.logger.Error("Failed to get alerted channels", zap.Error(err), zap.String("incident_id", incident.ID))
+		return monitor.NotificationConf.Channels
+	}
+	return channels
+}
+
 // AddIncidentComment adiciona um comentário ao incidente
 func (s *Service) AddIncidentComment(incidentID, tenantID, userEmail, comment string) error {
 	incident, err := s.repo.GetIncident(incidentID, tenantID)
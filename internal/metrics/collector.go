@@ -16,6 +16,9 @@ type Collector struct {
 	checksTotal       *prometheus.CounterVec
 	checkResponseCode *prometheus.GaugeVec
 
+	// Métricas HTTP
+	httpPhaseDuration *prometheus.HistogramVec
+
 	// Métricas SSL
 	sslDaysUntilExpiry *prometheus.GaugeVec
 	sslCertValid       *prometheus.GaugeVec
@@ -29,6 +32,11 @@ type Collector struct {
 	domainDaysUntilExpiry *prometheus.GaugeVec
 	domainValid           *prometheus.GaugeVec
 
+	// Métricas de Ping
+	pingRTT        *prometheus.GaugeVec
+	pingJitter     *prometheus.GaugeVec
+	pingPacketLoss *prometheus.GaugeVec
+
 	// === NOVAS MÉTRICAS ===
 
 	// SLA/SLO Metrics
@@ -111,6 +119,16 @@ func NewCollector(cfg config.MimirConfig) *Collector {
 			[]string{"tenant_id", "monitor_id", "monitor_name", "target", "region"},
 		),
 
+		// HTTP específicas
+		httpPhaseDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "uptime_http_phase_duration_seconds",
+				Help:    "Duration of HTTP check phases (dns, connect, tls, ttfb, transfer) in seconds",
+				Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+			},
+			[]string{"tenant_id", "monitor_id", "monitor_name", "target", "region", "phase"},
+		),
+
 		// SSL específicas
 		sslDaysUntilExpiry: promauto.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -171,6 +189,31 @@ func NewCollector(cfg config.MimirConfig) *Collector {
 			[]string{"tenant_id", "monitor_id", "monitor_name", "target"},
 		),
 
+		// Ping específicas
+		pingRTT: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ping_rtt_seconds",
+				Help: "Round-trip time of the last ping check by statistic (min, avg, max)",
+			},
+			[]string{"tenant_id", "monitor_id", "monitor_name", "target", "region", "stat"},
+		),
+
+		pingJitter: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ping_jitter_seconds",
+				Help: "Mean deviation between consecutive round-trip times of the last ping check",
+			},
+			[]string{"tenant_id", "monitor_id", "monitor_name", "target", "region"},
+		),
+
+		pingPacketLoss: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "ping_packet_loss_percent",
+				Help: "Percentage of echo requests without reply in the last ping check",
+			},
+			[]string{"tenant_id", "monitor_id", "monitor_name", "target", "region"},
+		),
+
 		// === NOVAS MÉTRICAS ===
 
 		// SLA/SLO Metrics
@@ -469,6 +512,20 @@ func (c *Collector) RecordCheck(result *db.CheckResult, monitor *db.Monitor) {
 			}).Set(float64(result.StatusCode))
 		}
 
+		// Phases that did not happen (e.g. TLS over plain HTTP) are left out
+		if timing, ok := result.Details["timing"].(map[string]float64); ok {
+			for phase, ms := range timing {
+				c.httpPhaseDuration.With(prometheus.Labels{
+					"tenant_id":    result.TenantID,
+					"monitor_id":   result.MonitorID,
+					"monitor_name": monitor.Name,
+					"target":       monitor.Target,
+					"region":       result.Region,
+					"phase":        phase,
+				}).Observe(ms / 1000)
+			}
+		}
+
 	case db.MonitorTypeSSL:
 		if days, ok := result.Details["days_until_expiry"].(float64); ok {
 			issuer := ""
@@ -553,6 +610,34 @@ func (c *Collector) RecordCheck(result *db.CheckResult, monitor *db.Monitor) {
 			"monitor_name": monitor.Name,
 			"target":       monitor.Target,
 		}).Set(validValue)
+
+	case db.MonitorTypePing:
+		labels := prometheus.Labels{
+			"tenant_id":    result.TenantID,
+			"monitor_id":   result.MonitorID,
+			"monitor_name": monitor.Name,
+			"target":       monitor.Target,
+			"region":       result.Region,
+		}
+
+		if loss, ok := result.Details["packet_loss"].(float64); ok {
+			c.pingPacketLoss.With(labels).Set(loss)
+		}
+		if jitter, ok := result.Details["jitter_ms"].(float64); ok {
+			c.pingJitter.With(labels).Set(jitter / 1000)
+		}
+		for _, stat := range []string{"min", "avg", "max"} {
+			if rtt, ok := result.Details["rtt_"+stat+"_ms"].(float64); ok {
+				c.pingRTT.With(prometheus.Labels{
+					"tenant_id":    result.TenantID,
+					"monitor_id":   result.MonitorID,
+					"monitor_name": monitor.Name,
+					"target":       monitor.Target,
+					"region":       result.Region,
+					"stat":         stat,
+				}).Set(rtt / 1000)
+			}
+		}
 	}
 }
 
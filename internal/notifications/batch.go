@@ -0,0 +1,181 @@
+package notifications
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// maxBatchFields caps how many alerts are listed in a batched message
+const maxBatchFields = 25
+
+// Digest periods a channel can collect alerts into
+const (
+	DigestHourly = "hourly"
+	DigestDaily  = "daily"
+)
+
+// batchable reports whether alerts for event may be held back and combined
+func batchable(event EventType) bool {
+	switch event {
+	case EventDown, EventDegraded, EventReminder, EventRecovery:
+		return true
+	}
+	return false
+}
+
+// NextDigest returns when the digest period containing now ends
+func NextDigest(period string, now time.Time) time.Time {
+	if period == DigestDaily {
+		year, month, day := now.Date()
+		return time.Date(year, month, day+1, 0, 0, 0, 0, now.Location())
+	}
+	return now.Truncate(time.Hour).Add(time.Hour)
+}
+
+// NewBatchMessage combines alerts held back by a channel's aggregation window or
+// digest into one message. Its group is set when all alerts share a monitor group.
+func NewBatchMessage(msgs []*Message, digest string) *Message {
+	return &Message{
+		Event:  batchEvent(msgs),
+		Group:  sharedGroup(msgs),
+		Batch:  msgs,
+		Digest: digest,
+		SentAt: time.Now(),
+	}
+}
+
+// sharedGroup returns the monitor group of the alerts if they all have the same
+// one. Digests collect alerts across groups.
+func sharedGroup(msgs []*Message) *db.MonitorGroup {
+	group := msgs[0].Group
+	for _, msg := range msgs[1:] {
+		if group == nil || msg.Group == nil || msg.Group.ID != group.ID {
+			return nil
+		}
+	}
+	return group
+}
+
+// batchEvent picks the most severe event in the batch
+func batchEvent(msgs []*Message) EventType {
+	rank := map[EventType]int{EventDown: 4, EventDegraded: 3, EventReminder: 2, EventRecovery: 1}
+	event := EventRecovery
+	for _, msg := range msgs {
+		if rank[msg.Event] > rank[event] {
+			event = msg.Event
+		}
+	}
+	return event
+}
+
+func batchTitle(msg *Message) string {
+	label := strings.ToUpper(string(msg.Event))
+	if msg.Event == EventRecovery {
+		label = "RECOVERED"
+	}
+	if !batchUniform(msg) {
+		label = "ALERTS"
+	}
+	if msg.Digest != "" {
+		label = strings.ToUpper(msg.Digest) + " DIGEST"
+	}
+
+	subject := pluralize(len(msg.Batch), "monitor")
+	if msg.Group != nil {
+		return fmt.Sprintf("[%s] %s in group %s", label, subject, msg.Group.Name)
+	}
+	return fmt.Sprintf("[%s] %s", label, subject)
+}
+
+// batchSummary reads like "14 monitors down, 2 recovered in group Payments"
+func batchSummary(msg *Message) string {
+	counts := make(map[EventType]int)
+	for _, m := range msg.Batch {
+		counts[m.Event]++
+	}
+
+	var parts []string
+	for _, event := range []EventType{EventDown, EventDegraded, EventReminder, EventRecovery} {
+		n := counts[event]
+		if n == 0 {
+			continue
+		}
+		state := string(event)
+		switch event {
+		case EventReminder:
+			state = "still failing"
+		case EventRecovery:
+			state = "recovered"
+		}
+		if len(parts) == 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", pluralize(n, "monitor"), state))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s", n, state))
+		}
+	}
+
+	summary := strings.Join(parts, ", ")
+	if msg.Group != nil {
+		summary += " in group " + msg.Group.Name
+	}
+	if msg.Digest != "" {
+		summary = fmt.Sprintf("%s%s digest: %s", strings.ToUpper(msg.Digest[:1]), msg.Digest[1:], summary)
+	}
+	return summary
+}
+
+// batchFields lists one line per alert, oldest first
+func batchFields(msg *Message) [][2]string {
+	alerts := make([]*Message, len(msg.Batch))
+	copy(alerts, msg.Batch)
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].SentAt.Before(alerts[j].SentAt)
+	})
+
+	var fields [][2]string
+	for i, alert := range alerts {
+		if i == maxBatchFields {
+			fields = append(fields, [2]string{"…", fmt.Sprintf("and %d more", len(alerts)-maxBatchFields)})
+			break
+		}
+		fields = append(fields, [2]string{alert.SubjectName(), alertState(alert)})
+	}
+	return fields
+}
+
+// alertState describes a single alert of a batch, e.g. "down: connection refused"
+func alertState(msg *Message) string {
+	switch msg.Event {
+	case EventRecovery:
+		if msg.Incident != nil {
+			return "recovered after " + FormatDowntime(msg.Incident.DowntimeMinutes)
+		}
+		return "recovered"
+	case EventReminder:
+		return "still " + currentStatus(msg)
+	}
+	if msg.Result != nil && msg.Result.Error != "" {
+		return fmt.Sprintf("%s: %s", msg.Event, msg.Result.Error)
+	}
+	return string(msg.Event)
+}
+
+func batchUniform(msg *Message) bool {
+	for _, m := range msg.Batch {
+		if m.Event != msg.Event {
+			return false
+		}
+	}
+	return true
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
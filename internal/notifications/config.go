@@ -0,0 +1,107 @@
+package notifications
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/leozw/uptime-guardian/internal/secrets"
+)
+
+// Helpers for reading the free-form db.NotificationChannel.Config map
+
+func configString(cfg map[string]interface{}, key string) string {
+	if v, ok := cfg[key]; ok {
+		if s, ok := v.(string); ok {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+func requireString(cfg map[string]interface{}, key string) (string, error) {
+	s := configString(cfg, key)
+	if s == "" {
+		return "", fmt.Errorf("missing required config field %q", key)
+	}
+	return s, nil
+}
+
+func configStringMap(cfg map[string]interface{}, key string) map[string]string {
+	result := make(map[string]string)
+	raw, ok := cfg[key].(map[string]interface{})
+	if !ok {
+		return result
+	}
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			result[k] = s
+		}
+	}
+	return result
+}
+
+// configStringSlice accepts either a JSON array of strings or a comma separated string
+func configStringSlice(cfg map[string]interface{}, key string) []string {
+	var result []string
+	switch v := cfg[key].(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				result = append(result, strings.TrimSpace(s))
+			}
+		}
+	case []string:
+		for _, s := range v {
+			if strings.TrimSpace(s) != "" {
+				result = append(result, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for _, s := range strings.Split(v, ",") {
+			if strings.TrimSpace(s) != "" {
+				result = append(result, strings.TrimSpace(s))
+			}
+		}
+	}
+	return result
+}
+
+// requireURL returns the http(s) URL stored under key
+func requireURL(cfg map[string]interface{}, key string) (string, error) {
+	s, err := requireString(cfg, key)
+	if err != nil {
+		return "", err
+	}
+	if err := checkURL(key, s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// optionalURL validates key only when it is set
+func optionalURL(cfg map[string]interface{}, key string) error {
+	s := configString(cfg, key)
+	if s == "" {
+		return nil
+	}
+	return checkURL(key, s)
+}
+
+// checkURL skips URLs with secret references, which are only known once the
+// worker resolves them
+func checkURL(key, raw string) error {
+	if secrets.HasReferences(raw) {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("config field %q must be an http(s) URL", key)
+	}
+	return nil
+}
+
+func configBool(cfg map[string]interface{}, key string) bool {
+	b, _ := cfg[key].(bool)
+	return b
+}
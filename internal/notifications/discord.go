@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// DiscordNotifier posts embeds to a Discord channel webhook.
+//
+// Channel config:
+//
+//	webhook_url  (required) Discord webhook URL
+//	username     optional override of the webhook's display name
+//	avatar_url   optional override of the webhook's avatar
+type DiscordNotifier struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewDiscordNotifier(baseURL string) *DiscordNotifier {
+	return &DiscordNotifier{
+		client:  newHTTPClient(),
+		baseURL: baseURL,
+	}
+}
+
+func (d *DiscordNotifier) Type() string {
+	return "discord"
+}
+
+func (d *DiscordNotifier) Handles(event EventType) bool {
+	return event != EventAcknowledged
+}
+
+func (d *DiscordNotifier) ValidateConfig(cfg map[string]interface{}) error {
+	if _, err := requireURL(cfg, "webhook_url"); err != nil {
+		return err
+	}
+	return optionalURL(cfg, "avatar_url")
+}
+
+type discordMessage struct {
+	Username  string         `json:"username,omitempty"`
+	AvatarURL string         `json:"avatar_url,omitempty"`
+	Embeds    []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	URL         string              `json:"url,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+	Footer      *discordEmbedFooter `json:"footer,omitempty"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+func (d *DiscordNotifier) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	webhookURL, err := requireURL(channel.Config, "webhook_url")
+	if err != nil {
+		return err
+	}
+
+	payload := &discordMessage{
+		Username:  configString(channel.Config, "username"),
+		AvatarURL: configString(channel.Config, "avatar_url"),
+		Embeds:    []discordEmbed{d.buildEmbed(msg)},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode discord message: %w", err)
+	}
+
+	_, err = postJSON(ctx, d.client, webhookURL, body, nil)
+	return err
+}
+
+func (d *DiscordNotifier) buildEmbed(msg *Message) discordEmbed {
+	embed := discordEmbed{
+		Title:       truncate(Title(msg), 253),
+		Description: Summary(msg),
+		URL:         Link(d.baseURL, msg),
+		Color:       discordColor(msg.Event),
+		Footer:      &discordEmbedFooter{Text: "Uptime Guardian"},
+	}
+	if !msg.SentAt.IsZero() {
+		embed.Timestamp = msg.SentAt.UTC().Format(time.RFC3339)
+	}
+
+	// Discord allows at most 25 fields of 1024 characters each
+	for _, f := range Fields(msg) {
+		if len(embed.Fields) == 25 {
+			break
+		}
+		embed.Fields = append(embed.Fields, discordEmbedField{
+			Name:   f[0],
+			Value:  truncate(f[1], 1021),
+			Inline: f[0] != "Error" && f[0] != "Target",
+		})
+	}
+
+	return embed
+}
+
+func discordColor(event EventType) int {
+	switch event {
+	case EventRecovery:
+		return 0x2f9e44
+	case EventDegraded:
+		return 0xe8590c
+	default:
+		return 0xc92a2a
+	}
+}
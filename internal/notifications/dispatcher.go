@@ -0,0 +1,184 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/metrics"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+	"go.uber.org/zap"
+)
+
+// Dispatcher routes messages to the notifier registered for each channel type
+// and records delivery metrics for every attempt. Secret references in channel
+// configs are resolved right before sending.
+type Dispatcher struct {
+	notifiers map[string]Notifier
+	metrics   *metrics.Collector
+	secrets   *secrets.Resolver
+	logger    *zap.Logger
+	timeout   time.Duration
+}
+
+func NewDispatcher(notifiers map[string]Notifier, metrics *metrics.Collector, secrets *secrets.Resolver, logger *zap.Logger, timeout time.Duration) *Dispatcher {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Dispatcher{
+		notifiers: notifiers,
+		metrics:   metrics,
+		secrets:   secrets,
+		logger:    logger,
+		timeout:   timeout,
+	}
+}
+
+// Supports reports whether a notifier is registered for the channel type
+func (d *Dispatcher) Supports(channelType string) bool {
+	_, ok := d.notifiers[channelType]
+	return ok
+}
+
+// Validate checks that the channel type is supported and its config is valid
+func (d *Dispatcher) Validate(channel db.NotificationChannel) error {
+	if channel.Type == ChannelTypeOnCall {
+		if OnCallScheduleID(channel) == "" {
+			return fmt.Errorf("invalid oncall channel config: schedule_id is required")
+		}
+	} else {
+		notifier, ok := d.notifiers[channel.Type]
+		if !ok {
+			return fmt.Errorf("unsupported notification channel type: %s", channel.Type)
+		}
+		if validator, ok := notifier.(ConfigValidator); ok {
+			if err := validator.ValidateConfig(channel.Config); err != nil {
+				return fmt.Errorf("invalid %s channel config: %w", channel.Type, err)
+			}
+		}
+	}
+
+	if err := ValidateSeverity(channel.MinSeverity); err != nil {
+		return err
+	}
+	if err := ValidateSchedule(channel.Schedule); err != nil {
+		return err
+	}
+
+	switch channel.Digest {
+	case "", DigestHourly, DigestDaily:
+	default:
+		return fmt.Errorf("digest must be %q or %q", DigestHourly, DigestDaily)
+	}
+	if channel.AggregationWindow < 0 {
+		return fmt.Errorf("aggregation_window must not be negative")
+	}
+	// Incident management tools track each incident on its own
+	if (channel.AggregationWindow > 0 || channel.Digest != "") && d.MirrorsLifecycle(channel.Type) {
+		return fmt.Errorf("%s channels cannot batch alerts", channel.Type)
+	}
+	return nil
+}
+
+// Handles reports whether the notifier for channelType wants to receive event
+func (d *Dispatcher) Handles(channelType string, event EventType) bool {
+	notifier, ok := d.notifiers[channelType]
+	if !ok {
+		// Unknown types still go through Send so the failure is recorded
+		return true
+	}
+	if filter, ok := notifier.(EventFilter); ok {
+		return filter.Handles(event)
+	}
+	return true
+}
+
+// MirrorsLifecycle reports whether channelType tracks incidents in an external system
+func (d *Dispatcher) MirrorsLifecycle(channelType string) bool {
+	notifier, ok := d.notifiers[channelType].(LifecycleNotifier)
+	return ok && notifier.MirrorsIncidentLifecycle()
+}
+
+// Send delivers msg through a single channel and returns the delivery error, if any.
+// Events the channel's notifier does not handle are skipped without error.
+func (d *Dispatcher) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	if !d.Handles(channel.Type, msg.Event) {
+		return nil
+	}
+
+	if msg.SentAt.IsZero() {
+		msg.SentAt = time.Now()
+	}
+
+	start := time.Now()
+	err := d.send(ctx, channel, msg)
+	latency := time.Since(start)
+
+	d.metrics.RecordNotificationSent(
+		msg.TenantID(),
+		msg.SubjectID(),
+		channel.Type,
+		err == nil,
+		latency.Seconds(),
+	)
+
+	if err != nil {
+		d.logger.Error("Failed to send notification",
+			zap.Error(err),
+			zap.String("channel_type", channel.Type),
+			zap.String("event", string(msg.Event)),
+			zap.String("subject_id", msg.SubjectID()),
+			zap.Duration("latency", latency),
+		)
+		return err
+	}
+
+	d.logger.Info("Notification sent",
+		zap.String("channel_type", channel.Type),
+		zap.String("event", string(msg.Event)),
+		zap.String("subject_id", msg.SubjectID()),
+		zap.Duration("latency", latency),
+	)
+	return nil
+}
+
+// SendAll delivers msg through every enabled channel and returns how many succeeded
+func (d *Dispatcher) SendAll(ctx context.Context, channels []db.NotificationChannel, msg *Message) int {
+	sent := 0
+	for _, channel := range channels {
+		if !channel.Enabled || !d.Handles(channel.Type, msg.Event) {
+			continue
+		}
+		if err := d.Send(ctx, channel, msg); err == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
+func (d *Dispatcher) send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	notifier, ok := d.notifiers[channel.Type]
+	if !ok {
+		return fmt.Errorf("unsupported notification channel type: %s", channel.Type)
+	}
+
+	channel, values, err := d.secrets.ResolveChannel(msg.TenantID(), channel)
+	if err != nil {
+		return fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	if err := notifier.Send(ctx, channel, msg); err != nil {
+		// Errors are logged and stored with the delivery, without the secrets or
+		// the credentials kept in plain text in the config
+		values = append(values, secrets.Credentials(channel.Config)...)
+		if len(values) > 0 {
+			return fmt.Errorf("%s notification failed: %s", channel.Type, values.Redact(err.Error()))
+		}
+		return fmt.Errorf("%s notification failed: %w", channel.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/config"
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+)
+
+// EmailNotifier sends multipart HTML/plain-text emails through an SMTP relay.
+//
+// Channel config:
+//
+//	to  (required) list of recipient addresses
+type EmailNotifier struct {
+	smtp    config.SMTPConfig
+	baseURL string
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+func NewEmailNotifier(cfg config.SMTPConfig, baseURL string) *EmailNotifier {
+	return &EmailNotifier{
+		smtp:    cfg,
+		baseURL: baseURL,
+		text:    texttemplate.Must(texttemplate.New("email").Parse(emailTextTemplates)),
+		html:    htmltemplate.Must(htmltemplate.New("email").Parse(emailHTMLTemplates)),
+	}
+}
+
+func (e *EmailNotifier) Type() string {
+	return "email"
+}
+
+func (e *EmailNotifier) Handles(event EventType) bool {
+	return event != EventAcknowledged
+}
+
+type emailTemplateData struct {
+	Title   string
+	Summary string
+	Fields  [][2]string
+	Link    string
+	Color   string
+}
+
+func (e *EmailNotifier) ValidateConfig(cfg map[string]interface{}) error {
+	to := configStringSlice(cfg, "to")
+	if len(to) == 0 {
+		return fmt.Errorf("missing required config field %q", "to")
+	}
+	for _, addr := range to {
+		if secrets.HasReferences(addr) {
+			continue
+		}
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", addr, err)
+		}
+	}
+	return nil
+}
+
+func (e *EmailNotifier) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	if e.smtp.Host == "" {
+		return fmt.Errorf("SMTP relay is not configured")
+	}
+
+	if err := e.ValidateConfig(channel.Config); err != nil {
+		return err
+	}
+	to := configStringSlice(channel.Config, "to")
+
+	body, err := e.render(msg, to)
+	if err != nil {
+		return err
+	}
+
+	return e.deliver(ctx, to, body)
+}
+
+func (e *EmailNotifier) render(msg *Message, to []string) ([]byte, error) {
+	data := emailTemplateData{
+		Title:   Title(msg),
+		Summary: Summary(msg),
+		Fields:  Fields(msg),
+		Link:    Link(e.baseURL, msg),
+		Color:   emailColor(msg.Event),
+	}
+
+	name := string(msg.Event)
+
+	var textBody, htmlBody bytes.Buffer
+	if err := e.text.ExecuteTemplate(&textBody, name, data); err != nil {
+		return nil, fmt.Errorf("failed to render text template: %w", err)
+	}
+	if err := e.html.ExecuteTemplate(&htmlBody, name, data); err != nil {
+		return nil, fmt.Errorf("failed to render html template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + e.smtp.From,
+		"To: " + strings.Join(to, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", data.Title),
+		"Date: " + msg.SentAt.Format(time.RFC1123Z),
+		"Message-ID: " + messageID(e.smtp.From),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + strconv.Quote(writer.Boundary()),
+	}
+	header := strings.Join(headers, "\r\n") + "\r\n\r\n"
+
+	for _, part := range []struct {
+		contentType string
+		body        []byte
+	}{
+		{"text/plain; charset=utf-8", textBody.Bytes()},
+		{"text/html; charset=utf-8", htmlBody.Bytes()},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.body); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return append([]byte(header), buf.Bytes()...), nil
+}
+
+func (e *EmailNotifier) deliver(ctx context.Context, to []string, body []byte) error {
+	port := e.smtp.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(e.smtp.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{
+		ServerName:         e.smtp.Host,
+		InsecureSkipVerify: e.smtp.InsecureSkipVerify,
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP relay: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	mode := strings.ToLower(e.smtp.TLSMode)
+	if mode == "tls" {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, e.smtp.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if mode == "starttls" || mode == "" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP relay does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if e.smtp.Username != "" {
+		auth := smtp.PlainAuth("", e.smtp.Username, e.smtp.Password, e.smtp.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	from, err := mail.ParseAddress(e.smtp.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("MAIL FROM rejected: %w", err)
+	}
+	for _, rcpt := range to {
+		// Recipients may carry a display name, e.g. "Ops <ops@example.com>"
+		addr, err := mail.ParseAddress(rcpt)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", rcpt, err)
+		}
+		if err := client.Rcpt(addr.Address); err != nil {
+			return fmt.Errorf("RCPT TO %s rejected: %w", addr.Address, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA rejected: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+
+	return client.Quit()
+}
+
+func emailColor(event EventType) string {
+	switch event {
+	case EventRecovery:
+		return "#2f9e44"
+	case EventDegraded:
+		return "#e8590c"
+	default:
+		return "#c92a2a"
+	}
+}
+
+func messageID(from string) string {
+	domain := "uptime-guardian.local"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			domain = addr.Address[at+1:]
+		}
+	}
+	b := make([]byte, 12)
+	rand.Read(b)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
@@ -0,0 +1,86 @@
+package notifications
+
+// Email bodies are rendered from one named template per event type.
+// The shared "details" templates list the fields returned by Fields().
+
+const emailTextTemplates = `
+{{define "details"}}{{range .Fields}}{{index . 0}}: {{index . 1}}
+{{end}}{{if .Link}}
+Open in Uptime Guardian: {{.Link}}
+{{end}}{{end}}
+
+{{define "down"}}ALERT: {{.Summary}}
+
+Uptime Guardian detected a failure and opened an incident.
+
+{{template "details" .}}{{end}}
+
+{{define "degraded"}}WARNING: {{.Summary}}
+
+The check is still responding but outside its expected thresholds.
+
+{{template "details" .}}{{end}}
+
+{{define "reminder"}}REMINDER: {{.Summary}}
+
+The incident is still open and has not been resolved yet.
+
+{{template "details" .}}{{end}}
+
+{{define "recovery"}}RESOLVED: {{.Summary}}
+
+The incident has been closed.
+
+{{template "details" .}}{{end}}
+
+{{define "test"}}TEST: {{.Summary}}
+
+If you can read this, the channel is configured correctly.
+
+{{template "details" .}}{{end}}
+`
+
+const emailHTMLTemplates = `
+{{define "layout-start"}}<!DOCTYPE html>
+<html>
+<body style="margin:0;padding:24px;background:#f4f5f7;font-family:Helvetica,Arial,sans-serif;color:#1f2933;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="max-width:600px;margin:0 auto;background:#ffffff;border-radius:6px;">
+<tr><td style="padding:16px 24px;background:{{.Color}};color:#ffffff;border-radius:6px 6px 0 0;font-size:18px;font-weight:bold;">{{.Title}}</td></tr>
+<tr><td style="padding:24px;">{{end}}
+
+{{define "details"}}<p style="font-size:16px;margin:0 0 16px 0;">{{.Summary}}</p>
+<table role="presentation" cellpadding="6" cellspacing="0" style="font-size:14px;border-collapse:collapse;">
+{{range .Fields}}<tr><td style="color:#616e7c;white-space:nowrap;">{{index . 0}}</td><td>{{index . 1}}</td></tr>
+{{end}}</table>
+{{if .Link}}<p style="margin:24px 0 0 0;"><a href="{{.Link}}" style="background:#3e4c59;color:#ffffff;padding:10px 16px;border-radius:4px;text-decoration:none;">Open in Uptime Guardian</a></p>{{end}}{{end}}
+
+{{define "layout-end"}}</td></tr>
+</table>
+</body>
+</html>{{end}}
+
+{{define "down"}}{{template "layout-start" .}}
+<p style="margin:0 0 16px 0;">Uptime Guardian detected a failure and opened an incident.</p>
+{{template "details" .}}
+{{template "layout-end" .}}{{end}}
+
+{{define "degraded"}}{{template "layout-start" .}}
+<p style="margin:0 0 16px 0;">The check is still responding but outside its expected thresholds.</p>
+{{template "details" .}}
+{{template "layout-end" .}}{{end}}
+
+{{define "reminder"}}{{template "layout-start" .}}
+<p style="margin:0 0 16px 0;">The incident is still open and has not been resolved yet.</p>
+{{template "details" .}}
+{{template "layout-end" .}}{{end}}
+
+{{define "recovery"}}{{template "layout-start" .}}
+<p style="margin:0 0 16px 0;">The incident has been closed.</p>
+{{template "details" .}}
+{{template "layout-end" .}}{{end}}
+
+{{define "test"}}{{template "layout-start" .}}
+<p style="margin:0 0 16px 0;">If you can read this, the channel is configured correctly.</p>
+{{template "details" .}}
+{{template "layout-end" .}}{{end}}
+`
@@ -0,0 +1,209 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Shared plain-text rendering used by the chat and email notifiers
+
+// Title returns a one line summary such as "[DOWN] Production API"
+func Title(msg *Message) string {
+	if msg.CustomTitle != "" {
+		return msg.CustomTitle
+	}
+	if len(msg.Batch) > 0 {
+		return batchTitle(msg)
+	}
+
+	label := strings.ToUpper(string(msg.Event))
+	switch msg.Event {
+	case EventRecovery:
+		label = "RECOVERED"
+	case EventReminder:
+		label = "STILL " + strings.ToUpper(string(currentStatus(msg)))
+	}
+
+	if msg.Group != nil && msg.Monitor == nil {
+		return fmt.Sprintf("[%s] Group %s", label, msg.Group.Name)
+	}
+	return fmt.Sprintf("[%s] %s", label, msg.SubjectName())
+}
+
+// Summary returns a short human readable description of what happened
+func Summary(msg *Message) string {
+	if msg.CustomBody != "" {
+		return msg.CustomBody
+	}
+	if len(msg.Batch) > 0 {
+		return batchSummary(msg)
+	}
+
+	if msg.Group != nil && msg.Monitor == nil {
+		if msg.Event == EventAcknowledged {
+			return fmt.Sprintf("Group %s incident acknowledged by %s", msg.Group.Name, acknowledgedBy(msg))
+		}
+		if msg.Escalation > 0 {
+			return fmt.Sprintf("Group %s is unhealthy and not acknowledged, escalated to level %d", msg.Group.Name, msg.Escalation)
+		}
+		if msg.Event == EventRecovery {
+			if msg.GroupInc != nil && msg.GroupInc.ResolvedAt != nil {
+				minutes := int(msg.GroupInc.ResolvedAt.Sub(msg.GroupInc.StartedAt).Minutes())
+				return fmt.Sprintf("Group %s is healthy again after %s", msg.Group.Name, FormatDowntime(minutes))
+			}
+			return fmt.Sprintf("Group %s is healthy again", msg.Group.Name)
+		}
+		if msg.GroupStat != nil {
+			return fmt.Sprintf("%s (health score %.1f)", msg.GroupStat.Message, msg.GroupStat.HealthScore)
+		}
+		return fmt.Sprintf("Group %s is unhealthy", msg.Group.Name)
+	}
+
+	if msg.Escalation > 0 {
+		return fmt.Sprintf("%s is %s and not acknowledged, escalated to level %d", msg.SubjectName(), currentStatus(msg), msg.Escalation)
+	}
+
+	switch msg.Event {
+	case EventRecovery:
+		if msg.Incident != nil {
+			return fmt.Sprintf("%s is operational again after %s of downtime", msg.SubjectName(), FormatDowntime(msg.Incident.DowntimeMinutes))
+		}
+		return fmt.Sprintf("%s is operational again", msg.SubjectName())
+	case EventAcknowledged:
+		return fmt.Sprintf("%s incident acknowledged by %s", msg.SubjectName(), acknowledgedBy(msg))
+	case EventTest:
+		return "This is a test notification from Uptime Guardian, no action is required"
+	case EventDegraded:
+		return fmt.Sprintf("%s is degraded", msg.SubjectName())
+	case EventReminder:
+		return fmt.Sprintf("%s is still %s", msg.SubjectName(), currentStatus(msg))
+	default:
+		return fmt.Sprintf("%s is down", msg.SubjectName())
+	}
+}
+
+// Fields returns the ordered key/value details shown in rich messages
+func Fields(msg *Message) [][2]string {
+	if len(msg.Batch) > 0 {
+		return batchFields(msg)
+	}
+
+	var fields [][2]string
+
+	if msg.Monitor != nil {
+		fields = append(fields,
+			[2]string{"Monitor", msg.Monitor.Name},
+			[2]string{"Target", msg.Monitor.Target},
+		)
+	}
+	if msg.Group != nil {
+		fields = append(fields, [2]string{"Group", msg.Group.Name})
+	}
+	if msg.Result != nil {
+		if msg.Result.Region != "" {
+			fields = append(fields, [2]string{"Region", msg.Result.Region})
+		}
+		if msg.Result.StatusCode > 0 {
+			fields = append(fields, [2]string{"Status Code", fmt.Sprintf("%d", msg.Result.StatusCode)})
+		}
+		if msg.Result.Error != "" && msg.Event != EventRecovery {
+			fields = append(fields, [2]string{"Error", msg.Result.Error})
+		}
+	}
+	if msg.GroupStat != nil {
+		fields = append(fields,
+			[2]string{"Health Score", fmt.Sprintf("%.1f", msg.GroupStat.HealthScore)},
+			[2]string{"Monitors Down", fmt.Sprintf("%d", msg.GroupStat.MonitorsDown)},
+		)
+	}
+	if msg.GroupInc != nil && msg.Event == EventRecovery {
+		if msg.GroupInc.ResolvedAt != nil {
+			minutes := int(msg.GroupInc.ResolvedAt.Sub(msg.GroupInc.StartedAt).Minutes())
+			fields = append(fields, [2]string{"Downtime", FormatDowntime(minutes)})
+		}
+		fields = append(fields, [2]string{"Affected Monitors", fmt.Sprintf("%d", len(msg.GroupInc.AffectedMonitors))})
+	}
+	if msg.Incident != nil {
+		fields = append(fields,
+			[2]string{"Severity", msg.Incident.Severity},
+			[2]string{"Downtime", FormatDowntime(msg.Incident.DowntimeMinutes)},
+		)
+		if msg.Event == EventRecovery {
+			fields = append(fields, [2]string{"Affected Checks", fmt.Sprintf("%d", msg.Incident.AffectedChecks)})
+		}
+		if msg.Event == EventAcknowledged {
+			fields = append(fields, [2]string{"Acknowledged By", acknowledgedBy(msg)})
+		}
+	}
+
+	if msg.Escalation > 0 {
+		fields = append(fields, [2]string{"Escalation", fmt.Sprintf("Level %d", msg.Escalation)})
+	}
+
+	return fields
+}
+
+// FormatDowntime renders minutes as "2h 5m"
+func FormatDowntime(minutes int) string {
+	if minutes < 1 {
+		return "< 1m"
+	}
+	h := minutes / 60
+	m := minutes % 60
+	if h == 0 {
+		return fmt.Sprintf("%dm", m)
+	}
+	return fmt.Sprintf("%dh %dm", h, m)
+}
+
+// Link returns the dashboard URL for the monitor or group, if a base URL is configured
+func Link(baseURL string, msg *Message) string {
+	if baseURL == "" {
+		return ""
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	if msg.Event == EventTest {
+		return baseURL
+	}
+	if msg.Monitor != nil {
+		return fmt.Sprintf("%s/monitors/%s", baseURL, msg.Monitor.ID)
+	}
+	if msg.Group != nil {
+		return fmt.Sprintf("%s/monitor-groups/%s", baseURL, msg.Group.ID)
+	}
+	return baseURL
+}
+
+// PlainText renders the message as a multi-line plain text body
+func PlainText(baseURL string, msg *Message) string {
+	var b strings.Builder
+	b.WriteString(Summary(msg))
+	b.WriteString("\n\n")
+	for _, f := range Fields(msg) {
+		fmt.Fprintf(&b, "%s: %s\n", f[0], f[1])
+	}
+	if link := Link(baseURL, msg); link != "" {
+		fmt.Fprintf(&b, "\n%s\n", link)
+	}
+	return b.String()
+}
+
+func currentStatus(msg *Message) string {
+	if msg.Result != nil && msg.Result.Status != "" {
+		return string(msg.Result.Status)
+	}
+	if msg.GroupStat != nil {
+		return string(msg.GroupStat.OverallStatus)
+	}
+	return "down"
+}
+
+func acknowledgedBy(msg *Message) string {
+	if msg.Incident != nil && msg.Incident.AcknowledgedBy != nil {
+		return *msg.Incident.AcknowledgedBy
+	}
+	if msg.GroupInc != nil && msg.GroupInc.AcknowledgedBy != nil {
+		return *msg.GroupInc.AcknowledgedBy
+	}
+	return "unknown"
+}
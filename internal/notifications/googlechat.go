@@ -0,0 +1,123 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// GoogleChatNotifier posts cards to a Google Chat space through an incoming webhook.
+//
+// Channel config:
+//
+//	webhook_url  (required) space webhook URL including key and token
+//
+// Messages about the same incident share a thread, keyed by the incident ID.
+type GoogleChatNotifier struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewGoogleChatNotifier(baseURL string) *GoogleChatNotifier {
+	return &GoogleChatNotifier{
+		client:  newHTTPClient(),
+		baseURL: baseURL,
+	}
+}
+
+func (g *GoogleChatNotifier) Type() string {
+	return "googlechat"
+}
+
+func (g *GoogleChatNotifier) Handles(event EventType) bool {
+	return event != EventAcknowledged
+}
+
+func (g *GoogleChatNotifier) ValidateConfig(cfg map[string]interface{}) error {
+	_, err := requireURL(cfg, "webhook_url")
+	return err
+}
+
+type googleChatMessage struct {
+	Text    string           `json:"text"`
+	CardsV2 []googleChatCard `json:"cardsV2"`
+	Thread  *googleChatRef   `json:"thread,omitempty"`
+}
+
+type googleChatRef struct {
+	ThreadKey string `json:"threadKey"`
+}
+
+type googleChatCard struct {
+	CardID string                 `json:"cardId"`
+	Card   map[string]interface{} `json:"card"`
+}
+
+func (g *GoogleChatNotifier) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	webhookURL, err := requireURL(channel.Config, "webhook_url")
+	if err != nil {
+		return err
+	}
+
+	endpoint, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook_url: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("messageReplyOption", "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+	endpoint.RawQuery = query.Encode()
+
+	body, err := json.Marshal(g.buildMessage(msg))
+	if err != nil {
+		return fmt.Errorf("failed to encode google chat message: %w", err)
+	}
+
+	_, err = postJSON(ctx, g.client, endpoint.String(), body, nil)
+	return err
+}
+
+func (g *GoogleChatNotifier) buildMessage(msg *Message) *googleChatMessage {
+	var widgets []map[string]interface{}
+	for _, f := range Fields(msg) {
+		widgets = append(widgets, map[string]interface{}{
+			"decoratedText": map[string]interface{}{
+				"topLabel": f[0],
+				"text":     f[1],
+				"wrapText": true,
+			},
+		})
+	}
+
+	if link := Link(g.baseURL, msg); link != "" {
+		widgets = append(widgets, map[string]interface{}{
+			"buttonList": map[string]interface{}{
+				"buttons": []map[string]interface{}{
+					{
+						"text":    "View in Uptime Guardian",
+						"onClick": map[string]interface{}{"openLink": map[string]string{"url": link}},
+					},
+				},
+			},
+		})
+	}
+
+	card := map[string]interface{}{
+		"header": map[string]string{
+			"title":    Title(msg),
+			"subtitle": Summary(msg),
+		},
+		"sections": []map[string]interface{}{
+			{"widgets": widgets},
+		},
+	}
+
+	return &googleChatMessage{
+		Text:    Title(msg),
+		CardsV2: []googleChatCard{{CardID: "uptime-guardian-alert", Card: card}},
+		Thread:  &googleChatRef{ThreadKey: msg.DedupKey()},
+	}
+}
@@ -0,0 +1,133 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// EventType identifies why a notification is being sent
+type EventType string
+
+const (
+	EventDown         EventType = "down"
+	EventDegraded     EventType = "degraded"
+	EventReminder     EventType = "reminder"
+	EventRecovery     EventType = "recovery"
+	EventAcknowledged EventType = "acknowledged"
+	EventTest         EventType = "test"
+)
+
+// Notifier delivers a message through a single kind of notification channel.
+// Implementations are keyed by db.NotificationChannel.Type.
+type Notifier interface {
+	Type() string
+	Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error
+}
+
+// EventFilter can be implemented by notifiers that only handle some events.
+// Notifiers without it receive every event.
+type EventFilter interface {
+	Handles(event EventType) bool
+}
+
+// ConfigValidator checks a channel config against the notifier's schema so
+// misconfigured channels are rejected when they are created, not when an alert fires.
+type ConfigValidator interface {
+	ValidateConfig(cfg map[string]interface{}) error
+}
+
+// LifecycleNotifier is implemented by notifiers that mirror the incident in an
+// external system (e.g. PagerDuty) and therefore must be told when it is
+// acknowledged or resolved, regardless of the recovery notification settings.
+type LifecycleNotifier interface {
+	Notifier
+	MirrorsIncidentLifecycle() bool
+}
+
+// Message carries everything a notifier may need to render an alert.
+// Monitor alerts set Monitor/Result/Incident, group alerts set the Group fields.
+type Message struct {
+	Event     EventType
+	Monitor   *db.Monitor
+	Result    *db.CheckResult
+	Incident  *db.Incident
+	Group     *db.MonitorGroup
+	GroupRule *db.MonitorGroupAlertRule
+	GroupInc  *db.MonitorGroupIncident
+	GroupStat *db.MonitorGroupStatus
+	SentAt    time.Time
+
+	// Batch holds the individual alerts when several are sent as one message
+	// (see NewBatchMessage); Digest names the digest period, if any
+	Batch  []*Message
+	Digest string
+
+	// Escalation is the 1-based escalation policy step an alert is sent for, 0 for
+	// the monitor's or group's own channels
+	Escalation int
+
+	// Rendered from the tenant's notification templates; when set they replace
+	// the built-in Title and Summary
+	CustomTitle string
+	CustomBody  string
+}
+
+// EventForStatus maps a failing check status to the alert event it triggers
+func EventForStatus(status db.CheckStatus) EventType {
+	if status == db.StatusDegraded {
+		return EventDegraded
+	}
+	return EventDown
+}
+
+// DedupKey returns the identifier used to correlate every message about the same incident
+func (m *Message) DedupKey() string {
+	if m.Incident != nil {
+		return m.Incident.ID
+	}
+	if m.GroupInc != nil {
+		return m.GroupInc.ID
+	}
+	if len(m.Batch) > 0 {
+		return "batch:" + m.SubjectID()
+	}
+	return m.SubjectID()
+}
+
+// TenantID returns the tenant the message belongs to
+func (m *Message) TenantID() string {
+	if m.Monitor != nil {
+		return m.Monitor.TenantID
+	}
+	if m.Group != nil {
+		return m.Group.TenantID
+	}
+	if len(m.Batch) > 0 {
+		return m.Batch[0].TenantID()
+	}
+	return ""
+}
+
+// SubjectID returns the monitor or group ID the message is about
+func (m *Message) SubjectID() string {
+	if m.Monitor != nil {
+		return m.Monitor.ID
+	}
+	if m.Group != nil {
+		return m.Group.ID
+	}
+	return ""
+}
+
+// SubjectName returns the monitor or group name the message is about
+func (m *Message) SubjectName() string {
+	if m.Monitor != nil {
+		return m.Monitor.Name
+	}
+	if m.Group != nil {
+		return m.Group.Name
+	}
+	return ""
+}
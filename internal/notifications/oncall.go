@@ -0,0 +1,168 @@
+package notifications
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// ChannelTypeOnCall pages whoever is on call for the schedule in config.schedule_id.
+// It is not a notifier: the outbox replaces it with the on-call member's contact
+// channels when an alert is queued.
+const ChannelTypeOnCall = "oncall"
+
+// OnCallShift is who is on call at a given time and why
+type OnCallShift struct {
+	User       string    `json:"user"`
+	Name       string    `json:"name,omitempty"`
+	Layer      string    `json:"layer,omitempty"`
+	OverrideID string    `json:"override_id,omitempty"`
+	Until      time.Time `json:"until"`
+}
+
+// WhoIsOnCall returns who is on call at t, nil when nobody is. Overrides win over
+// layers, and later layers over earlier ones. Until is the next handoff of the
+// layer (or the end of the override); restrictions of other layers may end the
+// shift earlier.
+func WhoIsOnCall(schedule *db.OnCallSchedule, overrides []*db.OnCallOverride, t time.Time) *OnCallShift {
+	var shift *OnCallShift
+
+	// The latest override wins when several overlap
+	var active *db.OnCallOverride
+	for _, o := range overrides {
+		if t.Before(o.StartsAt) || !t.Before(o.EndsAt) {
+			continue
+		}
+		if active == nil || o.StartsAt.After(active.StartsAt) {
+			active = o
+		}
+	}
+	if active != nil {
+		shift = &OnCallShift{User: active.User, OverrideID: active.ID, Until: active.EndsAt}
+	}
+
+	if shift == nil {
+		loc := scheduleLocation(&db.ChannelSchedule{Timezone: schedule.Timezone})
+		for i := len(schedule.Layers) - 1; i >= 0; i-- {
+			layer := schedule.Layers[i]
+			if !scheduleAllows(layerRestriction(schedule, layer), t) {
+				continue
+			}
+			user, until, ok := layerUser(layer, loc, t)
+			if !ok {
+				continue
+			}
+			shift = &OnCallShift{User: user, Layer: layer.Name, Until: until}
+			break
+		}
+	}
+
+	if shift != nil {
+		if member := schedule.Member(shift.User); member != nil {
+			shift.Name = member.Name
+		}
+	}
+	return shift
+}
+
+// layerUser returns who the layer's rotation has on call at t and when they hand off
+func layerUser(layer db.OnCallLayer, loc *time.Location, t time.Time) (string, time.Time, bool) {
+	if len(layer.Users) == 0 || t.Before(layer.Start) {
+		return "", time.Time{}, false
+	}
+	start := layer.Start.In(loc)
+	local := t.In(loc)
+
+	period := 1
+	if layer.Rotation == db.RotationWeekly {
+		period = 7
+	}
+
+	// Count calendar days so handoffs stay at the same local time across DST changes
+	days := int(civilDate(local).Sub(civilDate(start)).Hours() / 24)
+	if clockOf(local) < clockOf(start) {
+		days--
+	}
+	shifts := days / period
+
+	until := time.Date(start.Year(), start.Month(), start.Day()+(shifts+1)*period,
+		start.Hour(), start.Minute(), start.Second(), 0, loc)
+	return layer.Users[shifts%len(layer.Users)], until, true
+}
+
+func layerRestriction(schedule *db.OnCallSchedule, layer db.OnCallLayer) *db.ChannelSchedule {
+	if len(layer.Days) == 0 && layer.From == "" && layer.To == "" {
+		return nil
+	}
+	return &db.ChannelSchedule{
+		Timezone: schedule.Timezone,
+		Days:     layer.Days,
+		From:     layer.From,
+		To:       layer.To,
+	}
+}
+
+func civilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func clockOf(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// ValidateOnCallSchedule checks the timezone, layers and members of a schedule.
+// Contact channels are validated by the caller, which can resolve references.
+func ValidateOnCallSchedule(schedule *db.OnCallSchedule) error {
+	if _, err := time.LoadLocation(schedule.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", schedule.Timezone)
+	}
+
+	seen := make(map[string]bool)
+	for i, member := range schedule.Members {
+		if member.User == "" {
+			return fmt.Errorf("member %d: user is required", i)
+		}
+		if seen[member.User] {
+			return fmt.Errorf("member %s is listed twice", member.User)
+		}
+		seen[member.User] = true
+		if len(member.Channels) == 0 {
+			return fmt.Errorf("member %s: at least one contact channel is required", member.User)
+		}
+		for _, channel := range member.Channels {
+			if channel.Type == ChannelTypeOnCall {
+				return fmt.Errorf("member %s: contact channels cannot be on-call channels", member.User)
+			}
+		}
+	}
+
+	for i, layer := range schedule.Layers {
+		switch layer.Rotation {
+		case db.RotationDaily, db.RotationWeekly:
+		default:
+			return fmt.Errorf("layer %d: rotation must be %q or %q", i, db.RotationDaily, db.RotationWeekly)
+		}
+		if layer.Start.IsZero() {
+			return fmt.Errorf("layer %d: start is required", i)
+		}
+		if len(layer.Users) == 0 {
+			return fmt.Errorf("layer %d: at least one user is required", i)
+		}
+		for _, user := range layer.Users {
+			if !seen[user] {
+				return fmt.Errorf("layer %d: %s is not a member of the schedule", i, user)
+			}
+		}
+		if err := ValidateSchedule(layerRestriction(schedule, layer)); err != nil {
+			return fmt.Errorf("layer %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// OnCallScheduleID returns the schedule an on-call channel pages
+func OnCallScheduleID(channel db.NotificationChannel) string {
+	id, _ := channel.Config["schedule_id"].(string)
+	return id
+}
@@ -0,0 +1,132 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+func TestLayerUser(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+
+	daily := db.OnCallLayer{
+		Rotation: db.RotationDaily,
+		Start:    time.Date(2025, 3, 1, 9, 0, 0, 0, newYork),
+		Users:    []string{"ana", "bruno", "carla"},
+	}
+	weekly := db.OnCallLayer{
+		Rotation: db.RotationWeekly,
+		Start:    time.Date(2025, 3, 3, 10, 0, 0, 0, newYork),
+		Users:    []string{"ana", "bruno"},
+	}
+
+	tests := []struct {
+		name      string
+		layer     db.OnCallLayer
+		t         time.Time
+		wantUser  string
+		wantUntil time.Time
+		wantOK    bool
+	}{
+		{"before the layer starts", daily, time.Date(2025, 3, 1, 8, 59, 0, 0, newYork), "", time.Time{}, false},
+		{"first shift", daily, time.Date(2025, 3, 1, 9, 0, 0, 0, newYork),
+			"ana", time.Date(2025, 3, 2, 9, 0, 0, 0, newYork), true},
+		{"just before a handoff", daily, time.Date(2025, 3, 2, 8, 59, 0, 0, newYork),
+			"ana", time.Date(2025, 3, 2, 9, 0, 0, 0, newYork), true},
+		{"at a handoff", daily, time.Date(2025, 3, 2, 9, 0, 0, 0, newYork),
+			"bruno", time.Date(2025, 3, 3, 9, 0, 0, 0, newYork), true},
+		{"wraps around the users", daily, time.Date(2025, 3, 4, 12, 0, 0, 0, newYork),
+			"ana", time.Date(2025, 3, 5, 9, 0, 0, 0, newYork), true},
+
+		// Clocks jump from 02:00 to 03:00 on 2025-03-09 in New York; handoffs stay at 09:00 local
+		{"shift ending on DST change", daily, time.Date(2025, 3, 9, 8, 59, 0, 0, newYork),
+			"bruno", time.Date(2025, 3, 9, 9, 0, 0, 0, newYork), true},
+		{"handoff on DST change", daily, time.Date(2025, 3, 9, 13, 0, 0, 0, time.UTC),
+			"carla", time.Date(2025, 3, 10, 9, 0, 0, 0, newYork), true},
+		{"an hour earlier in UTC after DST change", daily, time.Date(2025, 3, 9, 12, 59, 0, 0, time.UTC),
+			"bruno", time.Date(2025, 3, 9, 9, 0, 0, 0, newYork), true},
+
+		{"weekly before the handoff", weekly, time.Date(2025, 3, 10, 9, 59, 0, 0, newYork),
+			"ana", time.Date(2025, 3, 10, 10, 0, 0, 0, newYork), true},
+		{"weekly at the handoff", weekly, time.Date(2025, 3, 10, 10, 0, 0, 0, newYork),
+			"bruno", time.Date(2025, 3, 17, 10, 0, 0, 0, newYork), true},
+		{"without users", db.OnCallLayer{Rotation: db.RotationDaily, Start: daily.Start},
+			time.Date(2025, 3, 2, 9, 0, 0, 0, newYork), "", time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, until, ok := layerUser(tt.layer, newYork, tt.t)
+			if ok != tt.wantOK || user != tt.wantUser || !until.Equal(tt.wantUntil) {
+				t.Errorf("layerUser(%s) = %q, %s, %v, want %q, %s, %v",
+					tt.t, user, until, ok, tt.wantUser, tt.wantUntil, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestWhoIsOnCall(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, 3, day, hour, minute, 0, 0, newYork)
+	}
+
+	schedule := &db.OnCallSchedule{
+		Timezone: "America/New_York",
+		Members: db.OnCallMembers{
+			{User: "ana", Name: "Ana"},
+			{User: "bruno", Name: "Bruno"},
+			{User: "carla", Name: "Carla"},
+			{User: "diego", Name: "Diego"},
+		},
+		Layers: db.OnCallLayers{
+			{Name: "primary", Rotation: db.RotationDaily, Start: at(1, 9, 0), Users: []string{"ana", "bruno"}},
+			{
+				Name:     "business hours",
+				Rotation: db.RotationWeekly,
+				Start:    at(3, 0, 0),
+				Users:    []string{"carla"},
+				Days:     []string{"mon", "tue", "wed", "thu", "fri"},
+				From:     "09:00",
+				To:       "17:00",
+			},
+		},
+	}
+
+	// Diego covers the primary handoff on Sunday 09:00, Carla takes a slice of it
+	overrides := []*db.OnCallOverride{
+		{ID: "cover", User: "diego", StartsAt: at(2, 8, 0), EndsAt: at(2, 10, 0)},
+		{ID: "swap", User: "carla", StartsAt: at(2, 9, 0).UTC(), EndsAt: at(2, 9, 30).UTC()},
+	}
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want *OnCallShift
+	}{
+		{"before any layer starts", at(1, 8, 0), nil},
+		{"primary layer", at(1, 12, 0), &OnCallShift{User: "ana", Name: "Ana", Layer: "primary", Until: at(2, 9, 0)}},
+		{"override before the handoff", at(2, 8, 30), &OnCallShift{User: "diego", Name: "Diego", OverrideID: "cover", Until: at(2, 10, 0)}},
+		{"later override wins", at(2, 9, 15), &OnCallShift{User: "carla", Name: "Carla", OverrideID: "swap", Until: at(2, 9, 30)}},
+		{"override after the handoff", at(2, 9, 30), &OnCallShift{User: "diego", Name: "Diego", OverrideID: "cover", Until: at(2, 10, 0)}},
+		{"layer after the override", at(2, 10, 0), &OnCallShift{User: "bruno", Name: "Bruno", Layer: "primary", Until: at(3, 9, 0)}},
+		{"restricted layer wins inside its window", at(3, 10, 0), &OnCallShift{User: "carla", Name: "Carla", Layer: "business hours", Until: at(10, 0, 0)}},
+		{"outside the restricted layer", at(3, 17, 0), &OnCallShift{User: "ana", Name: "Ana", Layer: "primary", Until: at(4, 9, 0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WhoIsOnCall(schedule, overrides, tt.t)
+			switch {
+			case got == nil || tt.want == nil:
+				if got != tt.want {
+					t.Errorf("WhoIsOnCall(%s) = %+v, want %+v", tt.t, got, tt.want)
+				}
+			case got.User != tt.want.User || got.Name != tt.want.Name || got.Layer != tt.want.Layer ||
+				got.OverrideID != tt.want.OverrideID || !got.Until.Equal(tt.want.Until):
+				t.Errorf("WhoIsOnCall(%s) = %+v, want %+v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+const defaultOpsgenieAPIURL = "https://api.opsgenie.com"
+
+// OpsgenieNotifier mirrors incidents into Opsgenie through the Alert API.
+//
+// Channel config:
+//
+//	api_key   (required) API integration key
+//	api_url   optional API base URL (e.g. https://api.eu.opsgenie.com), overrides the global default
+//	priority  optional fixed priority P1-P5, derived from the incident severity otherwise
+//	tags      optional list of tags added to the alert
+//
+// The incident ID is used as the alert alias, so the alert is acknowledged
+// and closed by alias when the incident is.
+type OpsgenieNotifier struct {
+	client  *http.Client
+	apiURL  string
+	baseURL string
+}
+
+func NewOpsgenieNotifier(apiURL, baseURL string) *OpsgenieNotifier {
+	if apiURL == "" {
+		apiURL = defaultOpsgenieAPIURL
+	}
+	return &OpsgenieNotifier{
+		client:  newHTTPClient(),
+		apiURL:  apiURL,
+		baseURL: baseURL,
+	}
+}
+
+func (o *OpsgenieNotifier) Type() string {
+	return "opsgenie"
+}
+
+// Reminders would only bump the count of the already open alert
+func (o *OpsgenieNotifier) Handles(event EventType) bool {
+	return event != EventReminder
+}
+
+func (o *OpsgenieNotifier) MirrorsIncidentLifecycle() bool {
+	return true
+}
+
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Source      string            `json:"source"`
+	Entity      string            `json:"entity,omitempty"`
+	Priority    string            `json:"priority,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+}
+
+type opsgenieAction struct {
+	User   string `json:"user,omitempty"`
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+func (o *OpsgenieNotifier) ValidateConfig(cfg map[string]interface{}) error {
+	if _, err := requireString(cfg, "api_key"); err != nil {
+		return err
+	}
+	switch configString(cfg, "priority") {
+	case "", "P1", "P2", "P3", "P4", "P5":
+	default:
+		return fmt.Errorf("config field %q must be one of P1-P5", "priority")
+	}
+	return optionalURL(cfg, "api_url")
+}
+
+func (o *OpsgenieNotifier) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	apiKey, err := requireString(channel.Config, "api_key")
+	if err != nil {
+		return err
+	}
+
+	apiURL := configString(channel.Config, "api_url")
+	if apiURL == "" {
+		apiURL = o.apiURL
+	}
+	alertsURL := strings.TrimRight(apiURL, "/") + "/v2/alerts"
+	headers := map[string]string{
+		"Authorization": "GenieKey " + apiKey,
+	}
+
+	var (
+		endpoint string
+		payload  interface{}
+	)
+	alias := msg.DedupKey()
+
+	switch msg.Event {
+	case EventAcknowledged:
+		endpoint = fmt.Sprintf("%s/%s/acknowledge?identifierType=alias", alertsURL, url.PathEscape(alias))
+		payload = &opsgenieAction{
+			User:   acknowledgedBy(msg),
+			Source: "uptime-guardian",
+			Note:   Summary(msg),
+		}
+	case EventRecovery:
+		endpoint = fmt.Sprintf("%s/%s/close?identifierType=alias", alertsURL, url.PathEscape(alias))
+		payload = &opsgenieAction{
+			Source: "uptime-guardian",
+			Note:   Summary(msg),
+		}
+	default:
+		endpoint = alertsURL
+		payload = o.buildAlert(channel, msg, alias)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode opsgenie request: %w", err)
+	}
+
+	// Opsgenie answers 202 and processes the request asynchronously
+	_, err = postJSON(ctx, o.client, endpoint, body, headers)
+	return err
+}
+
+func (o *OpsgenieNotifier) buildAlert(channel db.NotificationChannel, msg *Message, alias string) *opsgenieAlert {
+	details := make(map[string]string)
+	for _, f := range Fields(msg) {
+		details[f[0]] = f[1]
+	}
+
+	priority := configString(channel.Config, "priority")
+	if priority == "" {
+		priority = opsgeniePriority(msg)
+	}
+
+	tags := append([]string{"uptime-guardian"}, configStringSlice(channel.Config, "tags")...)
+
+	// Opsgenie limits the alert message to 130 characters
+	message := Title(msg)
+	if len(message) > 130 {
+		message = message[:127] + "..."
+	}
+
+	return &opsgenieAlert{
+		Message:     message,
+		Alias:       alias,
+		Description: PlainText(o.baseURL, msg),
+		Source:      "uptime-guardian",
+		Entity:      msg.SubjectName(),
+		Priority:    priority,
+		Tags:        tags,
+		Details:     details,
+	}
+}
+
+func opsgeniePriority(msg *Message) string {
+	severity := ""
+	if msg.Incident != nil {
+		severity = msg.Incident.Severity
+	} else if msg.GroupInc != nil {
+		severity = msg.GroupInc.Severity
+	}
+
+	switch severity {
+	case "critical":
+		return "P1"
+	case "warning":
+		return "P3"
+	case "info":
+		return "P5"
+	}
+	if msg.Event == EventDegraded {
+		return "P3"
+	}
+	return "P1"
+}
@@ -0,0 +1,642 @@
+package notifications
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/leozw/uptime-guardian/internal/config"
+	"github.com/leozw/uptime-guardian/internal/db"
+	"go.uber.org/zap"
+)
+
+// Outbox persists notifications in notification_deliveries and delivers them
+// in the background, retrying failures with exponential backoff. Producers
+// (check workers, the API) only enqueue, so a slow receiver never blocks them,
+// and pending deliveries survive restarts.
+type Outbox struct {
+	repo       *db.Repository
+	dispatcher *Dispatcher
+	templates  *TemplateRenderer
+	logger     *zap.Logger
+	cfg        config.OutboxConfig
+}
+
+func NewOutbox(repo *db.Repository, dispatcher *Dispatcher, templates *TemplateRenderer, logger *zap.Logger, cfg config.OutboxConfig) *Outbox {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 50
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 30 * time.Second
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = time.Hour
+	}
+	return &Outbox{
+		repo:       repo,
+		dispatcher: dispatcher,
+		templates:  templates,
+		logger:     logger,
+		cfg:        cfg,
+	}
+}
+
+// deliveryPayload is the stored snapshot a message is rebuilt from.
+// Monitor and group settings are stripped because they may carry credentials.
+type deliveryPayload struct {
+	Event      EventType                 `json:"event"`
+	Monitor    *db.Monitor               `json:"monitor,omitempty"`
+	Result     *db.CheckResult           `json:"check_result,omitempty"`
+	Incident   *db.Incident              `json:"incident,omitempty"`
+	Group      *db.MonitorGroup          `json:"group,omitempty"`
+	GroupRule  *db.MonitorGroupAlertRule `json:"group_rule,omitempty"`
+	GroupInc   *db.MonitorGroupIncident  `json:"group_incident,omitempty"`
+	GroupStat  *db.MonitorGroupStatus    `json:"group_status,omitempty"`
+	Escalation int                       `json:"escalation,omitempty"`
+}
+
+// Enqueue queues msg for every enabled channel that handles its event and
+// returns how many deliveries were queued
+func (o *Outbox) Enqueue(channels []db.NotificationChannel, msg *Message) int {
+	return o.enqueue(o.resolveChannels(msg.TenantID(), channels), msg)
+}
+
+// EnqueueLifecycle queues msg only for channels that mirror the incident lifecycle
+func (o *Outbox) EnqueueLifecycle(channels []db.NotificationChannel, msg *Message) int {
+	var lifecycle []db.NotificationChannel
+	for _, channel := range o.resolveChannels(msg.TenantID(), channels) {
+		if o.dispatcher.MirrorsLifecycle(channel.Type) {
+			lifecycle = append(lifecycle, channel)
+		}
+	}
+	return o.enqueue(lifecycle, msg)
+}
+
+func (o *Outbox) enqueue(channels []db.NotificationChannel, msg *Message) int {
+	payload, err := json.Marshal(newDeliveryPayload(msg))
+	if err != nil {
+		o.logger.Error("Failed to encode notification payload", zap.Error(err))
+		return 0
+	}
+
+	// Batched deliveries also carry the monitor group the alerts are combined by
+	var batchGroup *db.MonitorGroup
+	var batchPayload []byte
+	groupLoaded := false
+
+	queued := 0
+	for _, channel := range channels {
+		if !channel.Enabled || !o.dispatcher.Handles(channel.Type, msg.Event) {
+			continue
+		}
+		if !o.dispatcher.Supports(channel.Type) {
+			o.logger.Warn("Skipping unsupported notification channel",
+				zap.String("channel_type", channel.Type),
+				zap.String("subject_id", msg.SubjectID()),
+			)
+			continue
+		}
+
+		now := time.Now()
+		if !meetsSeverity(channel, msg) {
+			continue
+		}
+		if channel.Schedule != nil && channel.Schedule.OutsideWindow == OutsideWindowDrop && !scheduleAllows(channel.Schedule, now) {
+			o.logger.Debug("Dropping notification outside channel schedule",
+				zap.String("channel_type", channel.Type),
+				zap.String("subject_id", msg.SubjectID()),
+			)
+			continue
+		}
+
+		delivery := &db.NotificationDelivery{
+			ID:            uuid.New().String(),
+			TenantID:      msg.TenantID(),
+			ChannelType:   channel.Type,
+			Channel:       db.ChannelSnapshot(channel),
+			Event:         string(msg.Event),
+			DedupKey:      msg.DedupKey(),
+			Payload:       payload,
+			Status:        db.DeliveryPending,
+			MaxAttempts:   o.cfg.MaxAttempts,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		if msg.Monitor != nil {
+			delivery.MonitorID = &msg.Monitor.ID
+		}
+		if msg.Group != nil {
+			delivery.GroupID = &msg.Group.ID
+		}
+		if msg.Incident != nil {
+			delivery.IncidentID = &msg.Incident.ID
+		}
+		if msg.GroupInc != nil {
+			delivery.GroupIncidentID = &msg.GroupInc.ID
+		}
+
+		if o.batches(channel, msg.Event) {
+			if !groupLoaded {
+				batchGroup = o.batchGroup(msg)
+				batchPayload = o.encodeBatchPayload(msg, batchGroup, payload)
+				groupLoaded = true
+			}
+			key, due := o.batchSlot(channel, batchGroup, now)
+			delivery.BatchKey = &key
+			delivery.NextAttemptAt = due
+			delivery.Payload = batchPayload
+		}
+		// Outside quiet hours the delivery waits for the channel's window to open
+		delivery.NextAttemptAt = nextScheduleStart(channel.Schedule, delivery.NextAttemptAt)
+
+		if err := o.repo.CreateNotificationDelivery(delivery); err != nil {
+			o.logger.Error("Failed to queue notification",
+				zap.Error(err),
+				zap.String("channel_type", channel.Type),
+				zap.String("subject_id", msg.SubjectID()),
+			)
+			continue
+		}
+		queued++
+	}
+
+	return queued
+}
+
+// resolveChannels replaces references with the stored channels they point to, and
+// on-call channels with the contact channels of whoever is on call right now.
+// For references, the stored channel's enabled flag is authoritative.
+func (o *Outbox) resolveChannels(tenantID string, channels []db.NotificationChannel) []db.NotificationChannel {
+	resolved := o.resolveReferences(tenantID, channels)
+
+	var expanded []db.NotificationChannel
+	onCall := false
+	for _, channel := range resolved {
+		if channel.Type != ChannelTypeOnCall {
+			expanded = append(expanded, channel)
+			continue
+		}
+		onCall = true
+		if channel.Enabled {
+			expanded = append(expanded, o.onCallContacts(tenantID, channel, time.Now())...)
+		}
+	}
+	if !onCall {
+		return resolved
+	}
+	// Contact channels may be references, and the on-call person may already be
+	// among the channels
+	return o.resolveReferences(tenantID, expanded)
+}
+
+// onCallContacts returns the contact channels of the member on call for the
+// channel's schedule at now
+func (o *Outbox) onCallContacts(tenantID string, channel db.NotificationChannel, now time.Time) []db.NotificationChannel {
+	scheduleID := OnCallScheduleID(channel)
+	logger := o.logger.With(zap.String("schedule_id", scheduleID))
+
+	schedule, err := o.repo.GetOnCallSchedule(scheduleID, tenantID)
+	if err != nil {
+		logger.Error("Failed to get on-call schedule", zap.Error(err))
+		return nil
+	}
+	overrides, err := o.repo.GetOnCallOverrides(scheduleID, now, now)
+	if err != nil {
+		// Layers alone still page someone
+		logger.Error("Failed to get on-call overrides", zap.Error(err))
+	}
+
+	shift := WhoIsOnCall(schedule, overrides, now)
+	if shift == nil {
+		logger.Warn("Nobody is on call, skipping on-call channel", zap.String("schedule", schedule.Name))
+		return nil
+	}
+	member := schedule.Member(shift.User)
+	if member == nil {
+		logger.Warn("On-call user is not a member of the schedule", zap.String("user", shift.User))
+		return nil
+	}
+	return member.Channels
+}
+
+// resolveReferences replaces references with the stored channels they point to
+func (o *Outbox) resolveReferences(tenantID string, channels []db.NotificationChannel) []db.NotificationChannel {
+	var ids []string
+	for _, channel := range channels {
+		if channel.ID != "" {
+			ids = append(ids, channel.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return channels
+	}
+
+	stored, err := o.repo.GetNotificationChannelsByIDs(tenantID, ids)
+	if err != nil {
+		o.logger.Error("Failed to resolve notification channels", zap.Error(err))
+		stored = map[string]*db.NotificationChannel{}
+	}
+
+	resolved := make([]db.NotificationChannel, 0, len(channels))
+	seen := make(map[string]bool)
+	for _, channel := range channels {
+		if channel.ID == "" {
+			resolved = append(resolved, channel)
+			continue
+		}
+		// Snapshots of an edited channel differ, but must only be sent once
+		if seen[channel.ID] {
+			continue
+		}
+		seen[channel.ID] = true
+
+		ch, ok := stored[channel.ID]
+		if !ok {
+			o.logger.Warn("Skipping unknown notification channel", zap.String("channel_id", channel.ID))
+			continue
+		}
+		resolved = append(resolved, *ch)
+	}
+	return resolved
+}
+
+// errChannelUnavailable marks deliveries whose stored channel was deleted or disabled
+var errChannelUnavailable = errors.New("notification channel unavailable")
+
+// currentChannel returns the channel to deliver to. Stored channels are reloaded
+// so config fixes apply to pending retries.
+func (o *Outbox) currentChannel(d *db.NotificationDelivery) (db.NotificationChannel, error) {
+	channel := db.NotificationChannel(d.Channel)
+	if channel.ID == "" {
+		return channel, nil
+	}
+
+	stored, err := o.repo.GetNotificationChannel(channel.ID, d.TenantID)
+	if err != nil {
+		if err.Error() == "notification channel not found" {
+			return channel, fmt.Errorf("%w: %s was deleted", errChannelUnavailable, channel.ID)
+		}
+		return channel, err
+	}
+	if !stored.Enabled {
+		return channel, fmt.Errorf("%w: %s is disabled", errChannelUnavailable, stored.Name)
+	}
+	return *stored, nil
+}
+
+// Start delivers due notifications until ctx is cancelled
+func (o *Outbox) Start(ctx context.Context) {
+	o.logger.Info("Notification outbox started",
+		zap.Duration("poll_interval", o.cfg.PollInterval),
+		zap.Int("max_attempts", o.cfg.MaxAttempts),
+	)
+
+	ticker := time.NewTicker(o.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			o.logger.Info("Notification outbox stopped")
+			return
+		case <-ticker.C:
+			o.processDue(ctx)
+		}
+	}
+}
+
+func (o *Outbox) processDue(ctx context.Context) {
+	now := time.Now()
+	// The lease must outlast a send, otherwise another worker could pick the delivery up again
+	leaseUntil := now.Add(o.dispatcher.timeout + time.Minute)
+
+	deliveries, err := o.repo.ClaimDueNotificationDeliveries(now, leaseUntil, o.cfg.BatchSize)
+	if err != nil {
+		o.logger.Error("Failed to claim notification deliveries", zap.Error(err))
+		return
+	}
+
+	// Deliveries of the same batch become a single message
+	var singles []*db.NotificationDelivery
+	batches := make(map[string][]*db.NotificationDelivery)
+	for _, delivery := range deliveries {
+		if delivery.BatchKey == nil {
+			singles = append(singles, delivery)
+			continue
+		}
+		batches[*delivery.BatchKey] = append(batches[*delivery.BatchKey], delivery)
+	}
+	for _, batch := range batches {
+		if len(batch) == 1 {
+			singles = append(singles, batch[0])
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range singles {
+		wg.Add(1)
+		go func(d *db.NotificationDelivery) {
+			defer wg.Done()
+			o.deliver(ctx, d)
+		}(delivery)
+	}
+	for _, batch := range batches {
+		if len(batch) < 2 {
+			continue
+		}
+		wg.Add(1)
+		go func(b []*db.NotificationDelivery) {
+			defer wg.Done()
+			o.deliverBatch(ctx, b)
+		}(batch)
+	}
+	wg.Wait()
+}
+
+func (o *Outbox) deliver(ctx context.Context, d *db.NotificationDelivery) {
+	msg, err := o.buildMessage(d)
+	if err != nil {
+		// A payload that cannot be decoded will never succeed
+		o.fail(d, err)
+		return
+	}
+
+	channel, err := o.currentChannel(d)
+	if errors.Is(err, errChannelUnavailable) {
+		o.fail(d, err)
+		return
+	}
+	if err == nil {
+		o.templates.Apply(channel, msg)
+		err = o.dispatcher.Send(ctx, channel, msg)
+	}
+
+	if err != nil {
+		o.retry(d, err, time.Now().Add(o.backoff(d.Attempts)))
+		return
+	}
+
+	if err := o.repo.MarkNotificationDelivered(d.ID, time.Now()); err != nil {
+		o.logger.Error("Failed to mark notification delivered", zap.Error(err), zap.String("delivery_id", d.ID))
+	}
+
+	// Keep references created by the notifier (e.g. Slack thread ts) for follow-ups
+	if msg.Incident != nil && len(msg.Incident.NotificationRefs) > 0 {
+		if err := o.repo.MergeIncidentNotificationRefs(msg.Incident.ID, msg.Incident.NotificationRefs); err != nil {
+			o.logger.Error("Failed to store notification refs", zap.Error(err), zap.String("incident_id", msg.Incident.ID))
+		}
+	}
+}
+
+// deliverBatch sends deliveries held back by a channel's aggregation window or
+// digest as one message. They share the channel, so they succeed or fail together.
+func (o *Outbox) deliverBatch(ctx context.Context, batch []*db.NotificationDelivery) {
+	var msgs []*Message
+	var deliveries []*db.NotificationDelivery
+	for _, d := range batch {
+		msg, err := o.buildMessage(d)
+		if err != nil {
+			o.fail(d, err)
+			continue
+		}
+		msg.SentAt = d.CreatedAt
+		msgs = append(msgs, msg)
+		deliveries = append(deliveries, d)
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+
+	channel, err := o.currentChannel(deliveries[0])
+	if errors.Is(err, errChannelUnavailable) {
+		for _, d := range deliveries {
+			o.fail(d, err)
+		}
+		return
+	}
+	if err == nil {
+		var msg *Message
+		if len(msgs) == 1 {
+			msg = msgs[0]
+			o.templates.Apply(channel, msg)
+		} else {
+			msg = NewBatchMessage(msgs, channel.Digest)
+		}
+		err = o.dispatcher.Send(ctx, channel, msg)
+	}
+
+	if err != nil {
+		// One retry time keeps the batch together
+		next := time.Now().Add(o.backoff(deliveries[0].Attempts))
+		for _, d := range deliveries {
+			o.retry(d, err, next)
+		}
+		return
+	}
+
+	now := time.Now()
+	for _, d := range deliveries {
+		if err := o.repo.MarkNotificationDelivered(d.ID, now); err != nil {
+			o.logger.Error("Failed to mark notification delivered", zap.Error(err), zap.String("delivery_id", d.ID))
+		}
+	}
+}
+
+// batches reports whether alerts for event are held back on channel
+func (o *Outbox) batches(channel db.NotificationChannel, event EventType) bool {
+	if !batchable(event) || o.dispatcher.MirrorsLifecycle(channel.Type) {
+		return false
+	}
+	return channel.Digest != "" || channel.AggregationWindow > 0
+}
+
+// batchSlot returns the batch key and send time for a held back alert. Alerts join
+// the channel's open batch, per monitor group when aggregating.
+func (o *Outbox) batchSlot(channel db.NotificationChannel, group *db.MonitorGroup, now time.Time) (string, time.Time) {
+	key := "channel:" + channelKey(channel)
+
+	if channel.Digest != "" {
+		return key + ":digest", NextDigest(channel.Digest, now.In(scheduleLocation(channel.Schedule)))
+	}
+
+	if group != nil {
+		key += ":group:" + group.ID
+	}
+	due := now.Add(time.Duration(channel.AggregationWindow) * time.Second)
+	open, err := o.repo.GetBatchDueTime(key)
+	if err != nil {
+		o.logger.Error("Failed to look up notification batch", zap.Error(err))
+	} else if open != nil {
+		due = *open
+	}
+	return key, due
+}
+
+// batchGroup returns the monitor group alerts about msg are combined by
+func (o *Outbox) batchGroup(msg *Message) *db.MonitorGroup {
+	if msg.Group != nil {
+		return msg.Group
+	}
+	if msg.Monitor == nil {
+		return nil
+	}
+
+	groups, err := o.repo.GetMonitorGroups(msg.Monitor.ID)
+	if err != nil {
+		o.logger.Warn("Failed to get monitor groups for batching", zap.Error(err))
+		return nil
+	}
+	for _, group := range groups {
+		if group.Enabled {
+			return group
+		}
+	}
+	return nil
+}
+
+// encodeBatchPayload adds the batch group to the stored payload so the combined
+// message can name it
+func (o *Outbox) encodeBatchPayload(msg *Message, group *db.MonitorGroup, payload []byte) []byte {
+	if group == nil || msg.Group != nil {
+		return payload
+	}
+	withGroup := *msg
+	withGroup.Group = group
+	encoded, err := json.Marshal(newDeliveryPayload(&withGroup))
+	if err != nil {
+		return payload
+	}
+	return encoded
+}
+
+// channelKey identifies a channel across deliveries; inline channels by their config
+func channelKey(channel db.NotificationChannel) string {
+	if channel.ID != "" {
+		return channel.ID
+	}
+	config, _ := json.Marshal(channel.Config)
+	sum := sha256.Sum256(append([]byte(channel.Type+":"), config...))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (o *Outbox) retry(d *db.NotificationDelivery, err error, next time.Time) {
+	if d.Attempts >= d.MaxAttempts {
+		o.fail(d, err)
+		return
+	}
+	if err := o.repo.RescheduleNotificationDelivery(d.ID, err.Error(), next); err != nil {
+		o.logger.Error("Failed to reschedule notification delivery", zap.Error(err), zap.String("delivery_id", d.ID))
+	}
+}
+
+func (o *Outbox) fail(d *db.NotificationDelivery, err error) {
+	o.logger.Error("Giving up on notification delivery",
+		zap.Error(err),
+		zap.String("delivery_id", d.ID),
+		zap.String("channel_type", d.ChannelType),
+		zap.Int("attempts", d.Attempts),
+	)
+	if err := o.repo.FailNotificationDelivery(d.ID, err.Error()); err != nil {
+		o.logger.Error("Failed to mark notification delivery failed", zap.Error(err), zap.String("delivery_id", d.ID))
+	}
+}
+
+// backoff returns InitialBackoff * 2^(attempt-1), capped at MaxBackoff, with up to 10% jitter
+func (o *Outbox) backoff(attempt int) time.Duration {
+	delay := o.cfg.InitialBackoff
+	for i := 1; i < attempt && delay < o.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > o.cfg.MaxBackoff {
+		delay = o.cfg.MaxBackoff
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/10+1))
+}
+
+// buildMessage rebuilds the message from the stored payload. The incident is
+// reloaded so notifiers see the latest state and references.
+func (o *Outbox) buildMessage(d *db.NotificationDelivery) (*Message, error) {
+	var payload deliveryPayload
+	if err := json.Unmarshal(d.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode notification payload: %w", err)
+	}
+
+	msg := &Message{
+		Event:      payload.Event,
+		Monitor:    payload.Monitor,
+		Result:     payload.Result,
+		Incident:   payload.Incident,
+		Group:      payload.Group,
+		GroupRule:  payload.GroupRule,
+		GroupInc:   payload.GroupInc,
+		GroupStat:  payload.GroupStat,
+		Escalation: payload.Escalation,
+	}
+
+	// Tenant IDs are not part of the JSON representation
+	if msg.Monitor != nil {
+		msg.Monitor.TenantID = d.TenantID
+	}
+	if msg.Group != nil {
+		msg.Group.TenantID = d.TenantID
+	}
+	if msg.GroupInc != nil {
+		msg.GroupInc.TenantID = d.TenantID
+	}
+	if msg.Incident != nil {
+		msg.Incident.TenantID = d.TenantID
+	}
+
+	if d.IncidentID != nil {
+		incident, err := o.repo.GetIncidentByID(*d.IncidentID)
+		if err != nil {
+			o.logger.Warn("Failed to reload incident, using snapshot", zap.Error(err), zap.String("incident_id", *d.IncidentID))
+		} else {
+			msg.Incident = incident
+		}
+	}
+
+	return msg, nil
+}
+
+func newDeliveryPayload(msg *Message) *deliveryPayload {
+	payload := &deliveryPayload{
+		Event:      msg.Event,
+		Result:     msg.Result,
+		Incident:   msg.Incident,
+		GroupInc:   msg.GroupInc,
+		GroupStat:  msg.GroupStat,
+		Escalation: msg.Escalation,
+	}
+	if msg.Monitor != nil {
+		monitor := *msg.Monitor
+		monitor.Config = db.MonitorConfig{}
+		monitor.NotificationConf = db.NotificationConfig{}
+		payload.Monitor = &monitor
+	}
+	if msg.Group != nil {
+		group := *msg.Group
+		group.NotificationConf = db.NotificationConfig{}
+		payload.Group = &group
+	}
+	if msg.GroupRule != nil {
+		rule := *msg.GroupRule
+		rule.NotificationChannels = nil
+		payload.GroupRule = &rule
+	}
+	return payload
+}
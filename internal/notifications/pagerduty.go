@@ -0,0 +1,188 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+const defaultPagerDutyAPIURL = "https://events.pagerduty.com"
+
+// PagerDutyNotifier mirrors incidents into PagerDuty through the Events API v2.
+//
+// Channel config:
+//
+//	routing_key  (required) integration key of the PagerDuty service
+//	api_url      optional Events API base URL, overrides the global default
+//
+// The incident ID is used as dedup_key, so the alert opened by the first
+// notification is acknowledged and resolved together with the incident.
+type PagerDutyNotifier struct {
+	client  *http.Client
+	apiURL  string
+	baseURL string
+}
+
+func NewPagerDutyNotifier(apiURL, baseURL string) *PagerDutyNotifier {
+	if apiURL == "" {
+		apiURL = defaultPagerDutyAPIURL
+	}
+	return &PagerDutyNotifier{
+		client:  newHTTPClient(),
+		apiURL:  apiURL,
+		baseURL: baseURL,
+	}
+}
+
+func (p *PagerDutyNotifier) Type() string {
+	return "pagerduty"
+}
+
+// Reminders would only re-trigger the already open alert
+func (p *PagerDutyNotifier) Handles(event EventType) bool {
+	return event != EventReminder
+}
+
+func (p *PagerDutyNotifier) MirrorsIncidentLifecycle() bool {
+	return true
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+	Links       []pagerDutyLink   `json:"links,omitempty"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component,omitempty"`
+	Group         string            `json:"group,omitempty"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type pagerDutyLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+type pagerDutyResponse struct {
+	Status   string `json:"status"`
+	Message  string `json:"message"`
+	DedupKey string `json:"dedup_key"`
+}
+
+func (p *PagerDutyNotifier) ValidateConfig(cfg map[string]interface{}) error {
+	if _, err := requireString(cfg, "routing_key"); err != nil {
+		return err
+	}
+	return optionalURL(cfg, "api_url")
+}
+
+func (p *PagerDutyNotifier) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	routingKey, err := requireString(channel.Config, "routing_key")
+	if err != nil {
+		return err
+	}
+
+	apiURL := configString(channel.Config, "api_url")
+	if apiURL == "" {
+		apiURL = p.apiURL
+	}
+
+	event := &pagerDutyEvent{
+		RoutingKey:  routingKey,
+		EventAction: pagerDutyAction(msg.Event),
+		DedupKey:    msg.DedupKey(),
+	}
+	// Only trigger events carry a payload, acknowledge/resolve just reference the dedup key
+	if event.EventAction == "trigger" {
+		event.Payload = p.buildPayload(msg)
+		if link := Link(p.baseURL, msg); link != "" {
+			event.Links = []pagerDutyLink{{Href: link, Text: "View in Uptime Guardian"}}
+		}
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode pagerduty event: %w", err)
+	}
+
+	respBody, err := postJSON(ctx, p.client, strings.TrimRight(apiURL, "/")+"/v2/enqueue", body, nil)
+	if err != nil {
+		return err
+	}
+
+	var resp pagerDutyResponse
+	if err := json.Unmarshal(respBody, &resp); err == nil && resp.Status != "" && resp.Status != "success" {
+		return fmt.Errorf("pagerduty rejected event: %s", resp.Message)
+	}
+
+	return nil
+}
+
+func (p *PagerDutyNotifier) buildPayload(msg *Message) *pagerDutyPayload {
+	details := make(map[string]string)
+	for _, f := range Fields(msg) {
+		details[f[0]] = f[1]
+	}
+
+	payload := &pagerDutyPayload{
+		Summary:       truncate(Summary(msg), 1000),
+		Source:        "uptime-guardian",
+		Severity:      pagerDutySeverity(msg),
+		Component:     msg.SubjectName(),
+		CustomDetails: details,
+	}
+	if !msg.SentAt.IsZero() {
+		payload.Timestamp = msg.SentAt.UTC().Format(time.RFC3339)
+	}
+	if msg.Monitor != nil {
+		payload.Source = msg.Monitor.Target
+		payload.Class = string(msg.Monitor.Type)
+	}
+	if msg.Group != nil {
+		payload.Group = msg.Group.Name
+	}
+	return payload
+}
+
+func pagerDutyAction(event EventType) string {
+	switch event {
+	case EventAcknowledged:
+		return "acknowledge"
+	case EventRecovery:
+		return "resolve"
+	default:
+		return "trigger"
+	}
+}
+
+// pagerDutySeverity maps incident severity onto critical, error, warning or info
+func pagerDutySeverity(msg *Message) string {
+	severity := ""
+	if msg.Incident != nil {
+		severity = msg.Incident.Severity
+	} else if msg.GroupInc != nil {
+		severity = msg.GroupInc.Severity
+	}
+
+	switch severity {
+	case "critical", "error", "warning", "info":
+		return severity
+	}
+	if msg.Event == EventDegraded {
+		return "warning"
+	}
+	return "critical"
+}
@@ -0,0 +1,24 @@
+package notifications
+
+import "github.com/leozw/uptime-guardian/internal/config"
+
+// NewNotifiers returns every built-in notifier keyed by channel type
+func NewNotifiers(cfg config.NotificationsConfig) map[string]Notifier {
+	notifiers := []Notifier{
+		NewWebhookNotifier(),
+		NewSlackNotifier(cfg.DashboardURL),
+		NewEmailNotifier(cfg.SMTP, cfg.DashboardURL),
+		NewPagerDutyNotifier(cfg.PagerDutyURL, cfg.DashboardURL),
+		NewOpsgenieNotifier(cfg.OpsgenieURL, cfg.DashboardURL),
+		NewTeamsNotifier(cfg.DashboardURL),
+		NewDiscordNotifier(cfg.DashboardURL),
+		NewTelegramNotifier(cfg.DashboardURL),
+		NewGoogleChatNotifier(cfg.DashboardURL),
+	}
+
+	registry := make(map[string]Notifier, len(notifiers))
+	for _, n := range notifiers {
+		registry[n.Type()] = n
+	}
+	return registry
+}
@@ -0,0 +1,185 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// Severities as produced by incidents.Service, lowest first
+var severityRank = map[string]int{
+	"info":     1,
+	"warning":  2,
+	"critical": 3,
+}
+
+// Policies for alerts raised outside a channel's schedule
+const (
+	OutsideWindowQueue = "queue"
+	OutsideWindowDrop  = "drop"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Severity returns the severity of the incident the message is about. Messages
+// without an incident are rated by their event.
+func Severity(msg *Message) string {
+	if msg.Incident != nil && msg.Incident.Severity != "" {
+		return msg.Incident.Severity
+	}
+	if msg.GroupInc != nil && msg.GroupInc.Severity != "" {
+		return msg.GroupInc.Severity
+	}
+	if msg.Event == EventDegraded {
+		return "warning"
+	}
+	return "critical"
+}
+
+// meetsSeverity reports whether msg is severe enough for the channel
+func meetsSeverity(channel db.NotificationChannel, msg *Message) bool {
+	if channel.MinSeverity == "" || msg.Event == EventTest {
+		return true
+	}
+	return severityRank[Severity(msg)] >= severityRank[channel.MinSeverity]
+}
+
+// scheduleAllows reports whether t falls inside the schedule's window. Days
+// name the day a window starts on, so the part of a window spanning midnight
+// belongs to the day before.
+func scheduleAllows(s *db.ChannelSchedule, t time.Time) bool {
+	if s == nil {
+		return true
+	}
+	t = t.In(scheduleLocation(s))
+	day := t.Weekday()
+
+	if s.From != "" || s.To != "" {
+		from, _ := parseClock(s.From)
+		to, _ := parseClock(s.To)
+		if s.To == "" {
+			to = 24 * time.Hour
+		}
+		now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+
+		switch {
+		case from == to:
+		case from < to:
+			if now < from || now >= to {
+				return false
+			}
+		case now < to:
+			// The window spans midnight and started yesterday
+			day = (day + 6) % 7
+		case now < from:
+			return false
+		}
+	}
+
+	if len(s.Days) == 0 {
+		return true
+	}
+	for _, d := range s.Days {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// nextScheduleStart returns the first time at or after t inside the schedule's window
+func nextScheduleStart(s *db.ChannelSchedule, t time.Time) time.Time {
+	if scheduleAllows(s, t) {
+		return t
+	}
+
+	loc := scheduleLocation(s)
+	local := t.In(loc)
+	from, _ := parseClock(s.From)
+
+	// A window opens either at From or at midnight, within the next week
+	for day := 0; day <= 7; day++ {
+		midnight := time.Date(local.Year(), local.Month(), local.Day()+day, 0, 0, 0, 0, loc)
+		// From the wall clock, as adding from to midnight is off on DST changes
+		opens := time.Date(local.Year(), local.Month(), local.Day()+day,
+			int(from/time.Hour), int(from%time.Hour/time.Minute), 0, 0, loc)
+		for _, candidate := range []time.Time{midnight, opens} {
+			if candidate.After(t) && scheduleAllows(s, candidate) {
+				return candidate
+			}
+		}
+	}
+	return t
+}
+
+// ValidateSchedule checks timezone, days and times of a channel schedule
+func ValidateSchedule(s *db.ChannelSchedule) error {
+	if s == nil {
+		return nil
+	}
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("invalid schedule timezone %q", s.Timezone)
+		}
+	}
+	for _, day := range s.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("invalid schedule day %q, use mon, tue, wed, thu, fri, sat or sun", day)
+		}
+	}
+	for _, clock := range []string{s.From, s.To} {
+		if clock == "" {
+			continue
+		}
+		if _, err := parseClock(clock); err != nil {
+			return err
+		}
+	}
+	switch s.OutsideWindow {
+	case "", OutsideWindowQueue, OutsideWindowDrop:
+	default:
+		return fmt.Errorf("schedule outside_window must be %q or %q", OutsideWindowQueue, OutsideWindowDrop)
+	}
+	return nil
+}
+
+// ValidateSeverity checks a channel's minimum severity
+func ValidateSeverity(severity string) error {
+	if _, ok := severityRank[severity]; severity != "" && !ok {
+		return fmt.Errorf("min_severity must be critical, warning or info")
+	}
+	return nil
+}
+
+func scheduleLocation(s *db.ChannelSchedule) *time.Location {
+	if s == nil || s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseClock parses "HH:MM" into the offset from midnight
+func parseClock(clock string) (time.Duration, error) {
+	if clock == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schedule time %q, use HH:MM", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"testing"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestScheduleAllows(t *testing.T) {
+	saoPaulo := loadLocation(t, "America/Sao_Paulo")
+	newYork := loadLocation(t, "America/New_York")
+
+	weekdays := []string{"mon", "tue", "wed", "thu", "fri"}
+	business := &db.ChannelSchedule{Timezone: "America/Sao_Paulo", Days: weekdays, From: "09:00", To: "18:00"}
+	overnight := &db.ChannelSchedule{Days: weekdays, From: "22:00", To: "06:00"}
+	lateEvening := &db.ChannelSchedule{From: "22:00"}
+	earlyMorning := &db.ChannelSchedule{Timezone: "America/New_York", From: "01:00", To: "04:00"}
+	saturdays := &db.ChannelSchedule{Days: []string{"Sat"}}
+
+	tests := []struct {
+		name     string
+		schedule *db.ChannelSchedule
+		t        time.Time
+		want     bool
+	}{
+		{"no schedule", nil, time.Date(2025, 3, 8, 3, 0, 0, 0, time.UTC), true},
+
+		{"business hours open", business, time.Date(2025, 3, 3, 9, 0, 0, 0, saoPaulo), true},
+		{"business hours before opening", business, time.Date(2025, 3, 3, 8, 59, 0, 0, saoPaulo), false},
+		{"business hours close at to", business, time.Date(2025, 3, 3, 18, 0, 0, 0, saoPaulo), false},
+		{"business hours on saturday", business, time.Date(2025, 3, 8, 10, 0, 0, 0, saoPaulo), false},
+		{"business hours in another timezone", business, time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC), true},
+
+		{"overnight evening of a weekday", overnight, time.Date(2025, 3, 3, 22, 0, 0, 0, time.UTC), true},
+		{"overnight after friday's window", overnight, time.Date(2025, 3, 8, 2, 0, 0, 0, time.UTC), true},
+		{"overnight saturday evening", overnight, time.Date(2025, 3, 8, 22, 0, 0, 0, time.UTC), false},
+		{"overnight after sunday", overnight, time.Date(2025, 3, 3, 2, 0, 0, 0, time.UTC), false},
+		{"overnight after monday", overnight, time.Date(2025, 3, 4, 2, 0, 0, 0, time.UTC), true},
+		{"overnight closes at to", overnight, time.Date(2025, 3, 4, 6, 0, 0, 0, time.UTC), false},
+		{"overnight during the day", overnight, time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC), false},
+
+		{"open-ended window", lateEvening, time.Date(2025, 3, 4, 23, 59, 0, 0, time.UTC), true},
+		{"open-ended window before from", lateEvening, time.Date(2025, 3, 4, 21, 59, 0, 0, time.UTC), false},
+
+		// Clocks jump from 02:00 to 03:00 on 2025-03-09 in New York
+		{"before DST change", earlyMorning, time.Date(2025, 3, 9, 6, 30, 0, 0, time.UTC), true},
+		{"after DST change", earlyMorning, time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC), true},
+		{"closes at local to after DST change", earlyMorning, time.Date(2025, 3, 9, 8, 0, 0, 0, time.UTC), false},
+		{"local time after DST change", earlyMorning, time.Date(2025, 3, 9, 3, 59, 0, 0, newYork), true},
+
+		{"days only", saturdays, time.Date(2025, 3, 8, 23, 0, 0, 0, time.UTC), true},
+		{"days only on another day", saturdays, time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scheduleAllows(tt.schedule, tt.t); got != tt.want {
+				t.Errorf("scheduleAllows(%s) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextScheduleStart(t *testing.T) {
+	saoPaulo := loadLocation(t, "America/Sao_Paulo")
+	newYork := loadLocation(t, "America/New_York")
+
+	weekdays := []string{"mon", "tue", "wed", "thu", "fri"}
+	business := &db.ChannelSchedule{Timezone: "America/Sao_Paulo", Days: weekdays, From: "09:00", To: "18:00"}
+	overnight := &db.ChannelSchedule{Days: weekdays, From: "22:00", To: "06:00"}
+	daytime := &db.ChannelSchedule{Timezone: "America/New_York", From: "09:00", To: "17:00"}
+
+	tests := []struct {
+		name     string
+		schedule *db.ChannelSchedule
+		t        time.Time
+		want     time.Time
+	}{
+		{"inside the window", business,
+			time.Date(2025, 3, 3, 10, 0, 0, 0, saoPaulo), time.Date(2025, 3, 3, 10, 0, 0, 0, saoPaulo)},
+		{"later the same day", business,
+			time.Date(2025, 3, 3, 7, 0, 0, 0, saoPaulo), time.Date(2025, 3, 3, 9, 0, 0, 0, saoPaulo)},
+		{"over the weekend", business,
+			time.Date(2025, 3, 7, 19, 0, 0, 0, saoPaulo), time.Date(2025, 3, 10, 9, 0, 0, 0, saoPaulo)},
+		{"overnight window on saturday", overnight,
+			time.Date(2025, 3, 8, 10, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 22, 0, 0, 0, time.UTC)},
+		{"overnight window after sunday", overnight,
+			time.Date(2025, 3, 10, 3, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 22, 0, 0, 0, time.UTC)},
+		{"opens at local time on DST change", daytime,
+			time.Date(2025, 3, 8, 20, 0, 0, 0, newYork), time.Date(2025, 3, 9, 9, 0, 0, 0, newYork)},
+		{"opens at local time after DST ends", daytime,
+			time.Date(2025, 11, 1, 20, 0, 0, 0, newYork), time.Date(2025, 11, 2, 9, 0, 0, 0, newYork)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextScheduleStart(tt.schedule, tt.t); !got.Equal(tt.want) {
+				t.Errorf("nextScheduleStart(%s) = %s, want %s", tt.t, got, tt.want)
+			}
+		})
+	}
+}
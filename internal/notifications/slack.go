@@ -0,0 +1,223 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+const defaultSlackAPIURL = "https://slack.com/api"
+
+// SlackNotifier sends Block Kit messages to Slack.
+//
+// Channel config, either:
+//
+//	webhook_url  incoming webhook URL (no threading support)
+//
+// or:
+//
+//	bot_token    bot token with chat:write scope
+//	channel      channel ID or name to post to
+//	api_url      optional Slack API base URL, defaults to https://slack.com/api
+//
+// With a bot token the message ts of the first alert is stored on the incident
+// so reminders and the recovery message are posted in the same thread.
+type SlackNotifier struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewSlackNotifier(baseURL string) *SlackNotifier {
+	return &SlackNotifier{
+		client:  newHTTPClient(),
+		baseURL: baseURL,
+	}
+}
+
+func (s *SlackNotifier) Type() string {
+	return "slack"
+}
+
+func (s *SlackNotifier) Handles(event EventType) bool {
+	return event != EventAcknowledged
+}
+
+type slackMessage struct {
+	Channel  string       `json:"channel,omitempty"`
+	Text     string       `json:"text"`
+	Blocks   []slackBlock `json:"blocks"`
+	ThreadTS string       `json:"thread_ts,omitempty"`
+	// Also surface the recovery in the channel, not only in the thread
+	ReplyBroadcast bool `json:"reply_broadcast,omitempty"`
+}
+
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackAPIResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+	TS    string `json:"ts"`
+}
+
+func (s *SlackNotifier) ValidateConfig(cfg map[string]interface{}) error {
+	if configString(cfg, "webhook_url") != "" {
+		return optionalURL(cfg, "webhook_url")
+	}
+	if _, err := requireString(cfg, "bot_token"); err != nil {
+		return fmt.Errorf("either webhook_url or bot_token must be configured: %w", err)
+	}
+	if _, err := requireString(cfg, "channel"); err != nil {
+		return err
+	}
+	return optionalURL(cfg, "api_url")
+}
+
+func (s *SlackNotifier) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	payload := s.buildMessage(msg)
+
+	if webhookURL := configString(channel.Config, "webhook_url"); webhookURL != "" {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode slack message: %w", err)
+		}
+		_, err = postJSON(ctx, s.client, webhookURL, body, nil)
+		return err
+	}
+
+	token, err := requireString(channel.Config, "bot_token")
+	if err != nil {
+		return fmt.Errorf("either webhook_url or bot_token must be configured: %w", err)
+	}
+	slackChannel, err := requireString(channel.Config, "channel")
+	if err != nil {
+		return err
+	}
+
+	apiURL := configString(channel.Config, "api_url")
+	if apiURL == "" {
+		apiURL = defaultSlackAPIURL
+	}
+
+	payload.Channel = slackChannel
+
+	refKey := "slack:" + slackChannel
+	threadTS := incidentRef(msg.Incident, refKey)
+	if threadTS != "" && msg.Event != EventDown && msg.Event != EventDegraded {
+		payload.ThreadTS = threadTS
+		payload.ReplyBroadcast = msg.Event == EventRecovery
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	respBody, err := postJSON(ctx, s.client, strings.TrimRight(apiURL, "/")+"/chat.postMessage", body, map[string]string{
+		"Authorization": "Bearer " + token,
+	})
+	if err != nil {
+		return err
+	}
+
+	var resp slackAPIResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("invalid slack API response: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("slack API error: %s", resp.Error)
+	}
+
+	// Remember the root message so follow-ups are threaded under it
+	if threadTS == "" && resp.TS != "" {
+		setIncidentRef(msg.Incident, refKey, resp.TS)
+	}
+
+	return nil
+}
+
+func (s *SlackNotifier) buildMessage(msg *Message) *slackMessage {
+	title := Title(msg)
+
+	blocks := []slackBlock{
+		{
+			Type: "header",
+			Text: &slackText{Type: "plain_text", Text: slackEmoji(msg.Event) + " " + title},
+		},
+		{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: Summary(msg)},
+		},
+	}
+
+	var fields []slackText
+	for _, f := range Fields(msg) {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", f[0], f[1])})
+	}
+	// Slack allows at most 10 fields per section
+	for len(fields) > 0 {
+		n := len(fields)
+		if n > 10 {
+			n = 10
+		}
+		blocks = append(blocks, slackBlock{Type: "section", Fields: fields[:n]})
+		fields = fields[n:]
+	}
+
+	if link := Link(s.baseURL, msg); link != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("<%s|View in Uptime Guardian>", link)},
+		})
+	}
+
+	return &slackMessage{
+		Text:   title,
+		Blocks: blocks,
+	}
+}
+
+func slackEmoji(event EventType) string {
+	switch event {
+	case EventRecovery:
+		return ":white_check_mark:"
+	case EventDegraded:
+		return ":warning:"
+	case EventReminder:
+		return ":rotating_light:"
+	default:
+		return ":red_circle:"
+	}
+}
+
+func incidentRef(incident *db.Incident, key string) string {
+	if incident == nil || incident.NotificationRefs == nil {
+		return ""
+	}
+	if ref, ok := incident.NotificationRefs[key].(string); ok {
+		return ref
+	}
+	return ""
+}
+
+func setIncidentRef(incident *db.Incident, key, ref string) {
+	if incident == nil {
+		return
+	}
+	if incident.NotificationRefs == nil {
+		incident.NotificationRefs = make(db.JSONB)
+	}
+	incident.NotificationRefs[key] = ref
+}
@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// TeamsNotifier posts Adaptive Cards to a Microsoft Teams incoming webhook
+// (either a connector webhook or a Workflows "post to a channel" trigger).
+//
+// Channel config:
+//
+//	webhook_url  (required) Teams webhook URL
+type TeamsNotifier struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewTeamsNotifier(baseURL string) *TeamsNotifier {
+	return &TeamsNotifier{
+		client:  newHTTPClient(),
+		baseURL: baseURL,
+	}
+}
+
+func (t *TeamsNotifier) Type() string {
+	return "teams"
+}
+
+func (t *TeamsNotifier) Handles(event EventType) bool {
+	return event != EventAcknowledged
+}
+
+func (t *TeamsNotifier) ValidateConfig(cfg map[string]interface{}) error {
+	_, err := requireURL(cfg, "webhook_url")
+	return err
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string                   `json:"$schema"`
+	Type    string                   `json:"type"`
+	Version string                   `json:"version"`
+	Body    []map[string]interface{} `json:"body"`
+	Actions []map[string]interface{} `json:"actions,omitempty"`
+}
+
+func (t *TeamsNotifier) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	webhookURL, err := requireURL(channel.Config, "webhook_url")
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(t.buildMessage(msg))
+	if err != nil {
+		return fmt.Errorf("failed to encode teams message: %w", err)
+	}
+
+	_, err = postJSON(ctx, t.client, webhookURL, body, nil)
+	return err
+}
+
+func (t *TeamsNotifier) buildMessage(msg *Message) *teamsMessage {
+	var facts []map[string]string
+	for _, f := range Fields(msg) {
+		facts = append(facts, map[string]string{"title": f[0], "value": f[1]})
+	}
+
+	card := adaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body: []map[string]interface{}{
+			{
+				"type":   "TextBlock",
+				"text":   Title(msg),
+				"size":   "Large",
+				"weight": "Bolder",
+				"color":  teamsColor(msg.Event),
+				"wrap":   true,
+			},
+			{
+				"type": "TextBlock",
+				"text": Summary(msg),
+				"wrap": true,
+			},
+			{
+				"type":  "FactSet",
+				"facts": facts,
+			},
+		},
+	}
+
+	if link := Link(t.baseURL, msg); link != "" {
+		card.Actions = []map[string]interface{}{
+			{"type": "Action.OpenUrl", "title": "View in Uptime Guardian", "url": link},
+		}
+	}
+
+	return &teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{
+			{ContentType: "application/vnd.microsoft.card.adaptive", Content: card},
+		},
+	}
+}
+
+func teamsColor(event EventType) string {
+	switch event {
+	case EventRecovery:
+		return "Good"
+	case EventDegraded:
+		return "Warning"
+	default:
+		return "Attention"
+	}
+}
@@ -0,0 +1,178 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+	"github.com/leozw/uptime-guardian/internal/secrets"
+)
+
+const defaultTelegramAPIURL = "https://api.telegram.org"
+
+// TelegramNotifier sends HTML formatted messages through the Telegram Bot API.
+//
+// Channel config:
+//
+//	bot_token             (required) token issued by @BotFather
+//	chat_id               (required) numeric chat ID or @channel username
+//	message_thread_id     optional forum topic to post into
+//	disable_notification  optional, deliver silently
+//	api_url               optional Bot API base URL, defaults to https://api.telegram.org
+type TelegramNotifier struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewTelegramNotifier(baseURL string) *TelegramNotifier {
+	return &TelegramNotifier{
+		client:  newHTTPClient(),
+		baseURL: baseURL,
+	}
+}
+
+func (t *TelegramNotifier) Type() string {
+	return "telegram"
+}
+
+func (t *TelegramNotifier) Handles(event EventType) bool {
+	return event != EventAcknowledged
+}
+
+func (t *TelegramNotifier) ValidateConfig(cfg map[string]interface{}) error {
+	token, err := requireString(cfg, "bot_token")
+	if err != nil {
+		return err
+	}
+	// A secret reference is only checked once the worker resolves it
+	if !secrets.HasReferences(token) && !strings.Contains(token, ":") {
+		return fmt.Errorf("config field %q does not look like a bot token", "bot_token")
+	}
+	if _, err := requireString(cfg, "chat_id"); err != nil {
+		// Numeric chat IDs are commonly sent as JSON numbers
+		if _, ok := cfg["chat_id"].(float64); !ok {
+			return err
+		}
+	}
+	if v, ok := cfg["message_thread_id"]; ok {
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("config field %q must be a number", "message_thread_id")
+		}
+	}
+	return optionalURL(cfg, "api_url")
+}
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	MessageThreadID       int64  `json:"message_thread_id,omitempty"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+	DisableNotification   bool   `json:"disable_notification,omitempty"`
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (t *TelegramNotifier) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	if err := t.ValidateConfig(channel.Config); err != nil {
+		return err
+	}
+
+	apiURL := configString(channel.Config, "api_url")
+	if apiURL == "" {
+		apiURL = defaultTelegramAPIURL
+	}
+
+	payload := &telegramMessage{
+		ChatID:                telegramChatID(channel.Config),
+		Text:                  t.buildText(msg),
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+		DisableNotification:   configBool(channel.Config, "disable_notification"),
+	}
+	if threadID, ok := channel.Config["message_thread_id"].(float64); ok {
+		payload.MessageThreadID = int64(threadID)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode telegram message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimRight(apiURL, "/"), configString(channel.Config, "bot_token"))
+	respBody, err := postJSON(ctx, t.client, endpoint, body, nil)
+	if err != nil {
+		// The token is part of the URL, keep it out of logs
+		return fmt.Errorf("telegram API request failed: %s", strings.ReplaceAll(err.Error(), configString(channel.Config, "bot_token"), "<redacted>"))
+	}
+
+	var resp telegramResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("invalid telegram API response: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("telegram API error: %s", resp.Description)
+	}
+
+	return nil
+}
+
+// telegramMaxText is the room for the title, summary and fields. Telegram
+// rejects messages longer than 4096 characters, the link fits in the rest.
+const telegramMaxText = 4000
+
+// buildText renders the message using the small HTML subset Telegram supports.
+// Telegram counts characters after parsing the HTML, so the budget is spent on
+// the plain parts before they are escaped and tags, entities and the link are
+// never cut.
+func (t *TelegramNotifier) buildText(msg *Message) string {
+	budget := telegramMaxText
+	take := func(s string) string {
+		s = truncateRunes(s, budget)
+		budget -= utf8.RuneCountInString(s)
+		return html.EscapeString(s)
+	}
+
+	var b strings.Builder
+	budget -= len("\n\n\n")
+	fmt.Fprintf(&b, "<b>%s</b>\n%s\n\n", take(Title(msg)), take(Summary(msg)))
+	for _, f := range Fields(msg) {
+		budget -= len(": \n")
+		if budget <= 0 {
+			break
+		}
+		fmt.Fprintf(&b, "<b>%s:</b> %s\n", take(f[0]), take(f[1]))
+	}
+	if link := Link(t.baseURL, msg); link != "" {
+		fmt.Fprintf(&b, "\n<a href=\"%s\">View in Uptime Guardian</a>", html.EscapeString(link))
+	}
+	return b.String()
+}
+
+// truncateRunes shortens s to at most max characters, ending with an ellipsis
+// when cut
+func truncateRunes(s string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max-1]) + "…"
+}
+
+func telegramChatID(cfg map[string]interface{}) string {
+	if id, ok := cfg["chat_id"].(float64); ok {
+		return fmt.Sprintf("%.0f", id)
+	}
+	return configString(cfg, "chat_id")
+}
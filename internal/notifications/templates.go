@@ -0,0 +1,367 @@
+package notifications
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+	"go.uber.org/zap"
+)
+
+const (
+	maxTemplateSize = 4096
+	maxRenderedSize = 8192
+)
+
+// TemplateData is what title and body templates are executed against.
+// Monitor and group use the webhook representation, so credentials in their
+// config are never reachable from a template.
+type TemplateData struct {
+	Event   string
+	Title   string // built-in title, e.g. "[DOWN] Production API"
+	Summary string // built-in summary
+	Link    string
+	Fields  [][2]string
+
+	Monitor       *WebhookMonitor
+	Group         *WebhookGroup
+	Result        *db.CheckResult
+	Incident      *db.Incident
+	GroupIncident *db.MonitorGroupIncident
+	GroupStatus   *db.MonitorGroupStatus
+
+	// Tags of the monitor, or of the group for group alerts
+	Tags db.JSONB
+	// Details of the check result, e.g. days_until_expiry for SSL and domain checks
+	Details db.JSONB
+	// Escalation level the alert is sent for, 0 outside escalation policies
+	Escalation int
+}
+
+// templateFuncs is the complete function set available to templates. It only
+// formats values; nothing here can reach the network, the filesystem or the env.
+var templateFuncs = template.FuncMap{
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"trim":      strings.TrimSpace,
+	"replace":   strings.ReplaceAll,
+	"contains":  strings.Contains,
+	"hasPrefix": strings.HasPrefix,
+	"hasSuffix": strings.HasSuffix,
+	"join":      templateJoin,
+	"truncate":  templateTruncate,
+	"default":   templateDefault,
+	"formatTime": func(layout string, t interface{}) string {
+		switch v := t.(type) {
+		case time.Time:
+			return v.UTC().Format(layout)
+		case *time.Time:
+			if v != nil {
+				return v.UTC().Format(layout)
+			}
+		}
+		return ""
+	},
+	"downtime": FormatDowntime,
+}
+
+// ParseTemplate compiles a title or body template, rejecting oversized input
+func ParseTemplate(name, text string) (*template.Template, error) {
+	if len(text) > maxTemplateSize {
+		return nil, fmt.Errorf("%s template exceeds %d characters", name, maxTemplateSize)
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// TemplateRenderer applies the tenant's notification templates to outgoing messages
+type TemplateRenderer struct {
+	repo         *db.Repository
+	dashboardURL string
+	logger       *zap.Logger
+}
+
+func NewTemplateRenderer(repo *db.Repository, dashboardURL string, logger *zap.Logger) *TemplateRenderer {
+	return &TemplateRenderer{
+		repo:         repo,
+		dashboardURL: dashboardURL,
+		logger:       logger,
+	}
+}
+
+// Apply renders the most specific template for the channel and event into msg.
+// A broken template is logged and the built-in text is used, so an alert is never lost to it.
+func (r *TemplateRenderer) Apply(channel db.NotificationChannel, msg *Message) {
+	tmpl, err := r.repo.FindNotificationTemplate(msg.TenantID(), channel.ID, string(msg.Event))
+	if err != nil {
+		if err.Error() != "no notification template" {
+			r.logger.Error("Failed to load notification template", zap.Error(err))
+		}
+		return
+	}
+
+	title, body, err := r.Render(tmpl.Title, tmpl.Body, msg)
+	if err != nil {
+		r.logger.Warn("Failed to render notification template, using default",
+			zap.Error(err),
+			zap.String("template_id", tmpl.ID),
+		)
+		return
+	}
+	msg.CustomTitle = title
+	msg.CustomBody = body
+}
+
+// Render executes title and body against msg. Empty templates render empty,
+// which makes notifiers fall back to the built-in title or summary.
+func (r *TemplateRenderer) Render(title, body string, msg *Message) (string, string, error) {
+	data := r.templateData(msg)
+
+	renderedTitle, err := render("title", title, data)
+	if err != nil {
+		return "", "", err
+	}
+	renderedBody, err := render("body", body, data)
+	if err != nil {
+		return "", "", err
+	}
+	// Titles end up in subjects and headers
+	renderedTitle = strings.Join(strings.Fields(renderedTitle), " ")
+
+	return renderedTitle, strings.TrimSpace(renderedBody), nil
+}
+
+func (r *TemplateRenderer) templateData(msg *Message) *TemplateData {
+	// The built-in text must not pick up a previous custom rendering
+	plain := *msg
+	plain.CustomTitle = ""
+	plain.CustomBody = ""
+
+	payload := BuildWebhookPayload(&plain)
+	data := &TemplateData{
+		Event:         string(msg.Event),
+		Title:         Title(&plain),
+		Summary:       Summary(&plain),
+		Link:          Link(r.dashboardURL, &plain),
+		Fields:        Fields(&plain),
+		Monitor:       payload.Monitor,
+		Group:         payload.Group,
+		Result:        msg.Result,
+		Incident:      msg.Incident,
+		GroupIncident: msg.GroupInc,
+		GroupStatus:   msg.GroupStat,
+		Tags:          db.JSONB{},
+		Details:       db.JSONB{},
+		Escalation:    msg.Escalation,
+	}
+	if msg.Monitor != nil && msg.Monitor.Tags != nil {
+		data.Tags = msg.Monitor.Tags
+	} else if msg.Group != nil && msg.Group.Tags != nil {
+		data.Tags = msg.Group.Tags
+	}
+	if msg.Result != nil && msg.Result.Details != nil {
+		data.Details = msg.Result.Details
+	}
+	return data
+}
+
+func render(name, text string, data *TemplateData) (string, error) {
+	if strings.TrimSpace(text) == "" {
+		return "", nil
+	}
+	tmpl, err := ParseTemplate(name, text)
+	if err != nil {
+		return "", err
+	}
+
+	out := &limitedBuffer{limit: maxRenderedSize}
+	if err := tmpl.Execute(out, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", name, err)
+	}
+	return strings.ReplaceAll(out.String(), "<no value>", ""), nil
+}
+
+var errRenderTooLarge = errors.New("rendered output is too large")
+
+// limitedBuffer aborts template execution once limit bytes have been written
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errRenderTooLarge
+	}
+	return b.Buffer.Write(p)
+}
+
+func templateJoin(sep string, items interface{}) string {
+	switch v := items.(type) {
+	case []string:
+		return strings.Join(v, sep)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	case db.StringSlice:
+		return strings.Join(v, sep)
+	}
+	return fmt.Sprint(items)
+}
+
+func templateTruncate(length int, s string) string {
+	runes := []rune(s)
+	if length < 0 || len(runes) <= length {
+		return s
+	}
+	return string(runes[:length]) + "…"
+}
+
+// templateDefault returns value, or fallback when value is empty or missing
+func templateDefault(fallback, value interface{}) interface{} {
+	if value == nil {
+		return fallback
+	}
+	if s, ok := value.(string); ok && s == "" {
+		return fallback
+	}
+	return value
+}
+
+// SampleMessage builds a message for previews. Missing monitor or result are
+// replaced by made-up ones; the sample result carries the details the check
+// type would produce.
+func SampleMessage(tenantID string, event EventType, monitor *db.Monitor, result *db.CheckResult) *Message {
+	now := time.Now()
+
+	if monitor == nil {
+		monitor = &db.Monitor{
+			ID:      "sample",
+			Name:    "Production API",
+			Type:    db.MonitorTypeHTTP,
+			Target:  "https://api.example.com/health",
+			Regions: db.StringSlice{"us-east"},
+			Tags: db.JSONB{
+				"team":    "platform",
+				"runbook": "https://wiki.example.com/runbooks/production-api",
+			},
+		}
+	}
+	monitor.TenantID = tenantID
+
+	if result == nil {
+		result = sampleResult(monitor, event)
+	}
+
+	incident := &db.Incident{
+		ID:               "sample",
+		MonitorID:        monitor.ID,
+		TenantID:         tenantID,
+		StartedAt:        result.CheckedAt.Add(-15 * time.Minute),
+		Severity:         "critical",
+		DowntimeMinutes:  15,
+		AffectedChecks:   5,
+		NotificationRefs: make(db.JSONB),
+	}
+	switch event {
+	case EventRecovery:
+		incident.ResolvedAt = &result.CheckedAt
+	case EventAcknowledged:
+		by := "oncall@example.com"
+		incident.AcknowledgedAt = &now
+		incident.AcknowledgedBy = &by
+	case EventDegraded:
+		incident.Severity = "warning"
+	}
+
+	return &Message{
+		Event:    event,
+		Monitor:  monitor,
+		Result:   result,
+		Incident: incident,
+		SentAt:   now,
+	}
+}
+
+func sampleResult(monitor *db.Monitor, event EventType) *db.CheckResult {
+	region := "us-east"
+	if len(monitor.Regions) > 0 {
+		region = monitor.Regions[0]
+	}
+
+	result := &db.CheckResult{
+		ID:             "sample",
+		MonitorID:      monitor.ID,
+		TenantID:       monitor.TenantID,
+		Status:         db.StatusDown,
+		ResponseTimeMs: 1250,
+		Region:         region,
+		Details:        db.JSONB{},
+		CheckedAt:      time.Now(),
+	}
+
+	switch monitor.Type {
+	case db.MonitorTypeSSL:
+		expiry := time.Now().AddDate(0, 0, 7)
+		result.Error = "certificate expires in 7 days"
+		result.Details["days_until_expiry"] = 7
+		result.Details["issuer"] = "CN=R3,O=Let's Encrypt,C=US"
+		result.Details["subject"] = "CN=" + monitor.Target
+		result.Details["not_after"] = expiry.Format(time.RFC3339)
+	case db.MonitorTypeDomain:
+		expiry := time.Now().AddDate(0, 0, 20)
+		result.Error = "domain expires in 20 days"
+		result.Details["days_until_expiry"] = 20
+		result.Details["expiry_date"] = expiry.Format(time.RFC3339)
+	case db.MonitorTypeTCP:
+		result.Error = "connection failed: connection refused"
+		result.Details["connect_time_ms"] = 3
+	case db.MonitorTypeHTTPFlow:
+		result.StatusCode = 401
+		result.Error = "step login failed: unexpected status code: 401"
+		result.Details["failed_step"] = "login"
+		result.Details["failed_step_index"] = 0
+	case db.MonitorTypeHeartbeat:
+		result.Error = "no ping received in 1h5m0s, expected every 1h1m0s"
+		result.Details["last_ping_at"] = time.Now().Add(-65 * time.Minute).Format(time.RFC3339)
+	case db.MonitorTypeGRPC:
+		result.Error = "service is not serving"
+		result.Details["service"] = "payments.v1.Payments"
+		result.Details["serving_status"] = "NOT_SERVING"
+	case db.MonitorTypePing:
+		result.Error = "100% packet loss"
+		result.Details["packets_sent"] = 5
+		result.Details["packets_received"] = 0
+		result.Details["packet_loss"] = 100
+	case db.MonitorTypeDNS:
+		result.Error = "no records found"
+		result.Details["answers"] = []interface{}{}
+		result.Details["record_count"] = 0
+	default:
+		result.StatusCode = 503
+		result.Error = "unexpected status code: 503"
+	}
+
+	switch event {
+	case EventDegraded:
+		result.Status = db.StatusDegraded
+	case EventRecovery, EventTest:
+		result.Status = db.StatusUp
+		result.Error = ""
+		if result.StatusCode != 0 {
+			result.StatusCode = 200
+		}
+		result.ResponseTimeMs = 180
+	}
+	return result
+}
@@ -0,0 +1,186 @@
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+const (
+	// WebhookPayloadVersion is bumped whenever the payload shape changes incompatibly
+	WebhookPayloadVersion = "1"
+
+	WebhookSignatureHeader = "X-Uptime-Guardian-Signature"
+	WebhookTimestampHeader = "X-Uptime-Guardian-Timestamp"
+	WebhookEventHeader     = "X-Uptime-Guardian-Event"
+)
+
+// WebhookNotifier POSTs a versioned JSON payload to an arbitrary URL.
+//
+// Channel config:
+//
+//	url      (required) destination URL
+//	method   HTTP method, defaults to POST
+//	headers  map of extra request headers
+//	secret   when set, the request is signed with HMAC-SHA256
+//
+// The signature is computed over "<timestamp>.<body>" and sent as
+// "sha256=<hex>" so receivers can verify the sender and reject replays
+// by checking the timestamp header.
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{
+		client: newHTTPClient(),
+	}
+}
+
+func (w *WebhookNotifier) Type() string {
+	return "webhook"
+}
+
+type WebhookPayload struct {
+	Version     string                   `json:"version"`
+	Event       EventType                `json:"event"`
+	Timestamp   time.Time                `json:"timestamp"`
+	Title       string                   `json:"title"`
+	Summary     string                   `json:"summary"`
+	Monitor     *WebhookMonitor          `json:"monitor,omitempty"`
+	CheckResult *db.CheckResult          `json:"check_result,omitempty"`
+	Incident    *db.Incident             `json:"incident,omitempty"`
+	Group       *WebhookGroup            `json:"group,omitempty"`
+	GroupStatus *db.MonitorGroupStatus   `json:"group_status,omitempty"`
+	GroupInc    *db.MonitorGroupIncident `json:"group_incident,omitempty"`
+	// EscalationLevel is set when the alert was sent by an escalation policy step
+	EscalationLevel int `json:"escalation_level,omitempty"`
+	// Alerts lists the individual alerts of a batched message
+	Alerts []*WebhookPayload `json:"alerts,omitempty"`
+}
+
+// WebhookMonitor is the subset of db.Monitor exposed to receivers.
+// Config and notification settings are left out because they may carry credentials.
+type WebhookMonitor struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Target  string   `json:"target"`
+	Regions []string `json:"regions"`
+	Tags    db.JSONB `json:"tags,omitempty"`
+}
+
+type WebhookGroup struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Tags        db.JSONB `json:"tags,omitempty"`
+}
+
+func (w *WebhookNotifier) ValidateConfig(cfg map[string]interface{}) error {
+	if _, err := requireURL(cfg, "url"); err != nil {
+		return err
+	}
+	switch strings.ToUpper(configString(cfg, "method")) {
+	case "", http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return fmt.Errorf("config field %q must be POST, PUT or PATCH", "method")
+	}
+	if headers, ok := cfg["headers"]; ok {
+		if _, ok := headers.(map[string]interface{}); !ok {
+			return fmt.Errorf("config field %q must be an object", "headers")
+		}
+	}
+	return nil
+}
+
+func (w *WebhookNotifier) Send(ctx context.Context, channel db.NotificationChannel, msg *Message) error {
+	url, err := requireString(channel.Config, "url")
+	if err != nil {
+		return err
+	}
+
+	method := strings.ToUpper(configString(channel.Config, "method"))
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	body, err := json.Marshal(BuildWebhookPayload(msg))
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	headers := configStringMap(channel.Config, "headers")
+	headers[WebhookEventHeader] = string(msg.Event)
+
+	timestamp := strconv.FormatInt(msg.SentAt.Unix(), 10)
+	headers[WebhookTimestampHeader] = timestamp
+
+	if secret := configString(channel.Config, "secret"); secret != "" {
+		headers[WebhookSignatureHeader] = SignWebhook(secret, timestamp, body)
+	}
+
+	_, err = doRequest(ctx, w.client, method, url, body, headers)
+	return err
+}
+
+// BuildWebhookPayload converts a message into the public webhook payload
+func BuildWebhookPayload(msg *Message) *WebhookPayload {
+	payload := &WebhookPayload{
+		Version:     WebhookPayloadVersion,
+		Event:       msg.Event,
+		Timestamp:   msg.SentAt.UTC(),
+		Title:       Title(msg),
+		Summary:     Summary(msg),
+		CheckResult: msg.Result,
+		Incident:    msg.Incident,
+		GroupStatus: msg.GroupStat,
+		GroupInc:    msg.GroupInc,
+
+		EscalationLevel: msg.Escalation,
+	}
+
+	if msg.Monitor != nil {
+		payload.Monitor = &WebhookMonitor{
+			ID:      msg.Monitor.ID,
+			Name:    msg.Monitor.Name,
+			Type:    string(msg.Monitor.Type),
+			Target:  msg.Monitor.Target,
+			Regions: msg.Monitor.Regions,
+			Tags:    msg.Monitor.Tags,
+		}
+	}
+
+	if msg.Group != nil {
+		payload.Group = &WebhookGroup{
+			ID:          msg.Group.ID,
+			Name:        msg.Group.Name,
+			Description: msg.Group.Description,
+			Tags:        msg.Group.Tags,
+		}
+	}
+
+	for _, alert := range msg.Batch {
+		payload.Alerts = append(payload.Alerts, BuildWebhookPayload(alert))
+	}
+
+	return payload
+}
+
+// SignWebhook returns the signature header value for a webhook body
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/leozw/uptime-guardian/internal/config"
 	"github.com/leozw/uptime-guardian/internal/db"
 	"github.com/leozw/uptime-guardian/internal/metrics"
+	"github.com/leozw/uptime-guardian/internal/notifications"
+	"github.com/leozw/uptime-guardian/internal/secrets"
 	"go.uber.org/zap"
 )
 
@@ -16,17 +18,21 @@ type Scheduler struct {
 	repo         *db.Repository
 	metrics      *metrics.Collector
 	checkRunners map[string]checks.Runner
+	outbox       *notifications.Outbox
+	secrets      *secrets.Resolver
 	logger       *zap.Logger
 	config       *config.Config
 	workers      []*Worker
 	wg           sync.WaitGroup
 }
 
-func NewScheduler(repo *db.Repository, metrics *metrics.Collector, runners map[string]checks.Runner, logger *zap.Logger, cfg *config.Config) *Scheduler {
+func NewScheduler(repo *db.Repository, metrics *metrics.Collector, runners map[string]checks.Runner, outbox *notifications.Outbox, secrets *secrets.Resolver, logger *zap.Logger, cfg *config.Config) *Scheduler {
 	return &Scheduler{
 		repo:         repo,
 		metrics:      metrics,
 		checkRunners: runners,
+		outbox:       outbox,
+		secrets:      secrets,
 		logger:       logger,
 		config:       cfg,
 	}
@@ -40,7 +46,7 @@ func (s *Scheduler) Start(ctx context.Context) {
 	s.workers = make([]*Worker, s.config.Scheduler.WorkerCount)
 
 	for i := 0; i < s.config.Scheduler.WorkerCount; i++ {
-		worker := NewWorker(i, workQueue, s.repo, s.metrics, s.checkRunners, s.logger)
+		worker := NewWorker(i, workQueue, s.repo, s.metrics, s.checkRunners, s.outbox, s.secrets, s.logger)
 		s.workers[i] = worker
 		s.wg.Add(1)
 		go func(w *Worker) {
@@ -74,8 +80,13 @@ func (s *Scheduler) scheduleChecks(workQueue chan<- *CheckJob) {
 	}
 
 	for _, monitor := range monitors {
-		// Create job for each region
-		for _, region := range monitor.Regions {
+		// Create job for each region. Heartbeats are pinged, not checked from a
+		// region, so they are evaluated once.
+		regions := monitor.Regions
+		if monitor.Type == db.MonitorTypeHeartbeat && len(regions) > 1 {
+			regions = regions[:1]
+		}
+		for _, region := range regions {
 			job := &CheckJob{
 				Monitor: monitor,
 				Region:  region,
@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,6 +11,8 @@ import (
 	"github.com/leozw/uptime-guardian/internal/groups"
 	"github.com/leozw/uptime-guardian/internal/incidents"
 	"github.com/leozw/uptime-guardian/internal/metrics"
+	"github.com/leozw/uptime-guardian/internal/notifications"
+	"github.com/leozw/uptime-guardian/internal/secrets"
 	"go.uber.org/zap"
 )
 
@@ -22,9 +25,11 @@ type Worker struct {
 	logger          *zap.Logger
 	incidentService *incidents.Service
 	groupService    *groups.Service
+	outbox          *notifications.Outbox
+	secrets         *secrets.Resolver
 }
 
-func NewWorker(id int, workQueue <-chan *CheckJob, repo *db.Repository, metrics *metrics.Collector, runners map[string]checks.Runner, logger *zap.Logger) *Worker {
+func NewWorker(id int, workQueue <-chan *CheckJob, repo *db.Repository, metrics *metrics.Collector, runners map[string]checks.Runner, outbox *notifications.Outbox, secrets *secrets.Resolver, logger *zap.Logger) *Worker {
 	return &Worker{
 		id:              id,
 		workQueue:       workQueue,
@@ -32,8 +37,10 @@ func NewWorker(id int, workQueue <-chan *CheckJob, repo *db.Repository, metrics
 		metrics:         metrics,
 		checkRunners:    runners,
 		logger:          logger.With(zap.Int("worker_id", id)),
-		incidentService: incidents.NewService(repo, logger, metrics),
-		groupService:    groups.NewService(repo, logger, metrics),
+		incidentService: incidents.NewService(repo, logger, metrics, outbox),
+		groupService:    groups.NewService(repo, logger, metrics, outbox),
+		outbox:          outbox,
+		secrets:         secrets,
 	}
 }
 
@@ -73,8 +80,23 @@ func (w *Worker) processJob(job *CheckJob) {
 		return
 	}
 
-	// Execute check
-	result := checker.Check(job.Monitor, job.Region)
+	// Execute check with secret references resolved. A secret that cannot be
+	// resolved is not the target's fault, so it is a configuration error.
+	var result *db.CheckResult
+	monitor, values, err := w.secrets.ResolveMonitor(job.Monitor)
+	if err != nil {
+		result = &db.CheckResult{
+			MonitorID: job.Monitor.ID,
+			TenantID:  job.Monitor.TenantID,
+			Region:    job.Region,
+			Status:    db.StatusDegraded,
+			Error:     fmt.Sprintf("Failed to resolve secrets: %v", err),
+			Details:   db.JSONB{db.ConfigErrorDetail: true},
+		}
+	} else {
+		result = checker.Check(monitor, job.Region)
+		values.RedactResult(result)
+	}
 	result.ID = uuid.New().String()
 	result.CheckedAt = time.Now()
 
@@ -90,6 +112,16 @@ func (w *Worker) processJob(job *CheckJob) {
 	// Record metrics
 	w.metrics.RecordCheck(result, job.Monitor)
 
+	// A check that could not run, e.g. without credentials, says nothing about
+	// the target: incidents and groups are left as they are and nobody is paged
+	if result.IsConfigError() {
+		w.logger.Warn("Check failed on monitor configuration",
+			zap.String("monitor_id", job.Monitor.ID),
+			zap.String("error", result.Error),
+		)
+		return
+	}
+
 	// Process incidents
 	if err := w.incidentService.CreateOrUpdateIncident(job.Monitor, result); err != nil {
 		w.logger.Error("Failed to process incident",
@@ -152,8 +184,6 @@ func (w *Worker) processJob(job *CheckJob) {
 }
 
 func (w *Worker) processNotifications(monitor *db.Monitor, result *db.CheckResult) {
-	notificationStart := time.Now()
-
 	w.logger.Info("Processing notifications",
 		zap.String("monitor_id", monitor.ID),
 		zap.String("status", string(result.Status)),
@@ -173,48 +203,28 @@ func (w *Worker) processNotifications(monitor *db.Monitor, result *db.CheckResul
 			(monitor.NotificationConf.ReminderInterval > 0 &&
 				incident.AffectedChecks%monitor.NotificationConf.ReminderInterval == 0) {
 
-			for _, channel := range monitor.NotificationConf.Channels {
-				if channel.Enabled {
-					// Simulate notification sending
-					success := w.sendNotification(channel, monitor, result, incident)
-
-					// Record notification metrics
-					latency := time.Since(notificationStart).Seconds()
-					w.metrics.RecordNotificationSent(
-						monitor.TenantID,
-						monitor.ID,
-						channel.Type,
-						success,
-						latency,
-					)
-
-					if success {
-						incident.NotificationsSent++
-					}
-				}
+			event := notifications.EventForStatus(result.Status)
+			if incident.NotificationsSent > 0 {
+				event = notifications.EventReminder
 			}
 
+			msg := &notifications.Message{
+				Event:    event,
+				Monitor:  monitor,
+				Result:   result,
+				Incident: incident,
+			}
+
+			// Delivery happens in the outbox so slow receivers do not block checks
+			queued := w.outbox.Enqueue(monitor.NotificationConf.Channels, msg)
+			incident.NotificationsSent += queued
+
 			// Update incident with notification count
-			if err := w.repo.UpdateIncident(incident); err != nil {
+			if err := w.repo.AddIncidentNotificationsSent(incident.ID, queued); err != nil {
 				w.logger.Error("Failed to update incident notification count", zap.Error(err))
 			}
+
+			w.incidentService.StartEscalation(monitor, incident)
 		}
 	}
 }
-
-func (w *Worker) sendNotification(channel db.NotificationChannel, monitor *db.Monitor, result *db.CheckResult, incident *db.Incident) bool {
-	// TODO: Implement actual notification sending based on channel type
-	// For now, just log and simulate
-
-	w.logger.Info("Sending notification",
-		zap.String("channel_type", channel.Type),
-		zap.String("monitor_id", monitor.ID),
-		zap.String("monitor_name", monitor.Name),
-		zap.String("status", string(result.Status)),
-		zap.String("incident_id", incident.ID),
-		zap.Int("downtime_minutes", incident.DowntimeMinutes),
-	)
-
-	// Simulate success/failure (90% success rate)
-	return time.Now().UnixNano()%10 != 0
-}
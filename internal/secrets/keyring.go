@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+)
+
+// Keyring encrypts secrets with envelope encryption: every secret gets its own
+// random data key, which encrypts the value and is itself stored encrypted by
+// the master key. Both use AES-256-GCM.
+type Keyring struct {
+	master cipher.AEAD
+}
+
+// NewKeyring loads the master key, 32 bytes encoded as base64
+func NewKeyring(masterKey string) (*Keyring, error) {
+	key, err := base64.StdEncoding.DecodeString(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("master key must be base64 encoded: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("master key must be 32 bytes, got %d", len(key))
+	}
+	master, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Keyring{master: master}, nil
+}
+
+// Seal encrypts value under a new data key and returns the encrypted data key
+// and value. aad binds the ciphertext to its owner so it cannot be moved to
+// another secret.
+func (k *Keyring) Seal(value, aad []byte) (encryptedKey, encryptedValue []byte, err error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if encryptedValue, err = seal(data, value, aad); err != nil {
+		return nil, nil, err
+	}
+	if encryptedKey, err = seal(k.master, dataKey, aad); err != nil {
+		return nil, nil, err
+	}
+	return encryptedKey, encryptedValue, nil
+}
+
+// Open decrypts a value sealed with the same aad
+func (k *Keyring) Open(encryptedKey, encryptedValue, aad []byte) ([]byte, error) {
+	dataKey, err := open(k.master, encryptedKey, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	data, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	value, err := open(data, encryptedValue, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return value, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal prepends the random nonce to the ciphertext
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, ciphertext, aad []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, aad)
+}
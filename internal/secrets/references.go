@@ -0,0 +1,95 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// reference matches {{secret "name"}} in monitor and channel configs
+var reference = regexp.MustCompile(`\{\{\s*secret\s+"([A-Za-z0-9_.-]+)"\s*\}\}`)
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,255}$`)
+
+// ValidName reports whether name can be used in a {{secret "name"}} reference
+func ValidName(name string) bool {
+	return validName.MatchString(name)
+}
+
+// Reference returns the reference to the secret name, {{secret "name"}}
+func Reference(name string) string {
+	return `{{secret "` + name + `"}}`
+}
+
+// HasReferences reports whether s references a secret
+func HasReferences(s string) bool {
+	return strings.Contains(s, "{{") && reference.MatchString(s)
+}
+
+// References returns the names of the secrets referenced anywhere in v, a config
+// that marshals to JSON, each name once
+func References(v interface{}) ([]string, error) {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	_, err = walk(generic, func(s string) (string, error) {
+		for _, match := range reference.FindAllStringSubmatch(s, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				names = append(names, match[1])
+			}
+		}
+		return s, nil
+	})
+	return names, err
+}
+
+// toGeneric converts v to maps, slices and strings. Numbers are kept as
+// json.Number so they marshal back unchanged.
+func toGeneric(v interface{}) (interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// walk returns a copy of a generic JSON value with every string replaced by fn's result
+func walk(v interface{}, fn func(string) (string, error)) (interface{}, error) {
+	switch value := v.(type) {
+	case string:
+		return fn(value)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, child := range value {
+			replaced, err := walk(child, fn)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = replaced
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, child := range value {
+			replaced, err := walk(child, fn)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = replaced
+		}
+		return out, nil
+	}
+	return v, nil
+}
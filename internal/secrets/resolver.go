@@ -0,0 +1,237 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/leozw/uptime-guardian/internal/db"
+)
+
+// minRedactLength keeps very short values, which would match all over, from
+// being redacted
+const minRedactLength = 4
+
+const redacted = "[REDACTED]"
+
+// SealSecret encrypts value into the secret. The ciphertext is bound to the
+// secret's tenant and name, so a secret cannot be renamed once created.
+func (k *Keyring) SealSecret(secret *db.Secret, value string) error {
+	encryptedKey, encryptedValue, err := k.Seal([]byte(value), secretAAD(secret))
+	if err != nil {
+		return err
+	}
+	secret.EncryptedKey, secret.EncryptedValue = encryptedKey, encryptedValue
+	return nil
+}
+
+// OpenSecret decrypts the secret's value
+func (k *Keyring) OpenSecret(secret *db.Secret) (string, error) {
+	value, err := k.Open(secret.EncryptedKey, secret.EncryptedValue, secretAAD(secret))
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func secretAAD(secret *db.Secret) []byte {
+	return []byte(secret.TenantID + "/" + secret.Name)
+}
+
+// Resolver replaces secret references with their values. It is only used by the
+// worker, right before a check runs or a notification is sent, so resolved
+// values are never stored or returned by the API.
+type Resolver struct {
+	repo    *db.Repository
+	keyring *Keyring
+}
+
+// NewResolver returns a resolver; without a keyring every reference fails to resolve
+func NewResolver(repo *db.Repository, keyring *Keyring) *Resolver {
+	return &Resolver{repo: repo, keyring: keyring}
+}
+
+// Values are the secret values a config was resolved with
+type Values []string
+
+// ResolveMonitor returns a copy of the monitor with the references in its target
+// and config replaced, or the monitor itself when it has none
+func (r *Resolver) ResolveMonitor(monitor *db.Monitor) (*db.Monitor, Values, error) {
+	type fields struct {
+		Target string           `json:"target"`
+		Config db.MonitorConfig `json:"config"`
+	}
+	var out fields
+
+	values, err := r.resolve(monitor.TenantID, fields{monitor.Target, monitor.Config}, &out)
+	if err != nil || values == nil {
+		return monitor, nil, err
+	}
+
+	resolved := *monitor
+	resolved.Target, resolved.Config = out.Target, out.Config
+	return &resolved, values, nil
+}
+
+// ResolveChannel returns a copy of the channel with the references in its config replaced
+func (r *Resolver) ResolveChannel(tenantID string, channel db.NotificationChannel) (db.NotificationChannel, Values, error) {
+	var config db.JSONB
+	values, err := r.resolve(tenantID, channel.Config, &config)
+	if err != nil || values == nil {
+		return channel, nil, err
+	}
+	channel.Config = config
+	return channel, values, nil
+}
+
+// resolve decodes src with its references replaced into dst, which must be a
+// pointer to a zero value as decoding merges into maps. It returns nil values,
+// leaving dst untouched, when there is nothing to resolve.
+func (r *Resolver) resolve(tenantID string, src, dst interface{}) (Values, error) {
+	generic, err := toGeneric(src)
+	if err != nil {
+		return nil, err
+	}
+	names, err := References(generic)
+	if err != nil || len(names) == 0 {
+		return nil, err
+	}
+
+	secrets, err := r.lookup(tenantID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, err := walk(generic, func(s string) (string, error) {
+		return reference.ReplaceAllStringFunc(s, func(match string) string {
+			return secrets[reference.FindStringSubmatch(match)[1]]
+		}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(resolved)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return nil, fmt.Errorf("failed to apply secrets: %w", err)
+	}
+
+	values := make(Values, 0, len(secrets))
+	for _, value := range secrets {
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// lookup decrypts the tenant's secrets with the given names
+func (r *Resolver) lookup(tenantID string, names []string) (map[string]string, error) {
+	if r.keyring == nil {
+		return nil, fmt.Errorf("secrets are not configured, set a master key")
+	}
+
+	stored, err := r.repo.GetSecretsByNames(tenantID, names)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(stored))
+	for _, secret := range stored {
+		value, err := r.keyring.OpenSecret(secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt secret %q: %w", secret.Name, err)
+		}
+		values[secret.Name] = value
+	}
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("secret %q not found", name)
+		}
+	}
+	return values, nil
+}
+
+// Redact replaces the values in s
+func (v Values) Redact(s string) string {
+	for _, value := range v {
+		if len(value) >= minRedactLength {
+			s = strings.ReplaceAll(s, value, redacted)
+		}
+	}
+	return s
+}
+
+// RedactResult removes the values from a check result's error and details,
+// e.g. a token echoed in a response or a URL with a key in its query
+func (v Values) RedactResult(result *db.CheckResult) {
+	if len(v) == 0 {
+		return
+	}
+	result.Error = v.Redact(result.Error)
+	for key, detail := range result.Details {
+		result.Details[key] = v.redactDetail(detail)
+	}
+}
+
+// redactDetail returns detail with the values removed. Strings, maps and slices
+// keep their type, as readers of the details assert it (e.g. the metrics
+// collector reads HTTP timings as map[string]float64); other values, such as
+// structs, are only replaced by their JSON form when they hold a value.
+func (v Values) redactDetail(detail interface{}) interface{} {
+	switch value := detail.(type) {
+	case nil, bool, int, int64, float64, map[string]float64:
+		return detail
+	case string:
+		return v.Redact(value)
+	case []string:
+		out := make([]string, len(value))
+		for i, child := range value {
+			out[i] = v.Redact(child)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]string, len(value))
+		for k, child := range value {
+			out[k] = v.Redact(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(value))
+		for i, child := range value {
+			out[i] = v.redactDetail(child)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for k, child := range value {
+			out[k] = v.redactDetail(child)
+		}
+		return out
+	case db.JSONB:
+		return db.JSONB(v.redactDetail(map[string]interface{}(value)).(map[string]interface{}))
+	}
+
+	raw, err := json.Marshal(detail)
+	if err != nil {
+		return detail
+	}
+	text := string(raw)
+	for _, value := range v {
+		if len(value) < minRedactLength {
+			continue
+		}
+		// Match the value as it appears inside a JSON string
+		escaped, _ := json.Marshal(value)
+		text = strings.ReplaceAll(text, string(escaped[1:len(escaped)-1]), redacted)
+	}
+	if text == string(raw) {
+		return detail
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal([]byte(text), &generic); err != nil {
+		return detail
+	}
+	return generic
+}